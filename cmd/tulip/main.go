@@ -5,11 +5,11 @@ import (
 	"os"
 
 	"github.com/pierrestoffe/tulip/pkg/cli"
+	"github.com/pierrestoffe/tulip/pkg/util"
 )
 
 // main initializes and executes the Tulip CLI application
+// The exit code reflects the category of the error the command failed with
 func main() {
-	if err := cli.Execute(); err != nil {
-		os.Exit(1)
-	}
+	os.Exit(util.ExitCode(cli.Execute()))
 }
@@ -0,0 +1,180 @@
+// Package certs manages Tulip's local certificate authority and the certificates it issues
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// Validity period of the root certificate authority
+const caValidity = 10 * 365 * 24 * time.Hour
+
+// Authority holds the root certificate and key used to sign project certificates
+type Authority struct {
+	Cert *x509.Certificate
+	Key  *ecdsa.PrivateKey
+}
+
+// GetCAFilePath constructs the full path to the root certificate
+func GetCAFilePath() string {
+	return filepath.Join(config.GetCAConfigDirPath(), config.CertsCAFile)
+}
+
+// GetCAKeyFilePath constructs the full path to the root certificate's private key
+func GetCAKeyFilePath() string {
+	return filepath.Join(config.GetCAConfigDirPath(), config.CertsCAKeyFile)
+}
+
+// EnsureCA loads the local certificate authority, creating it if it doesn't exist yet
+// Returns true if a new certificate authority was created
+func EnsureCA() (*Authority, bool, error) {
+	ca, err := LoadCA()
+	if err == nil {
+		return ca, false, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, false, util.HandleError("Failed to load certificate authority", err)
+	}
+
+	ca, err = createCA()
+	if err != nil {
+		return nil, false, err
+	}
+	return ca, true, nil
+}
+
+// LoadCA reads the local certificate authority from its directory
+func LoadCA() (*Authority, error) {
+	certPEM, err := os.ReadFile(GetCAFilePath())
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(GetCAKeyFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &Authority{Cert: cert, Key: key}, nil
+}
+
+// createCA generates a new root certificate authority and stores it in its directory
+func createCA() (*Authority, error) {
+	util.PrintInfo("Creating " + config.AppName + " certificate authority..")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, util.HandleError("Failed to generate certificate authority key", err)
+	}
+
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization:       []string{config.AppName + " development CA"},
+			OrganizationalUnit: []string{hostname},
+			CommonName:         config.AppName + " root CA",
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, util.HandleError("Failed to create certificate authority", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, util.HandleError("Failed to parse certificate authority", err)
+	}
+
+	// Create CA directory if it doesn't exist
+	if err := os.MkdirAll(config.GetCAConfigDirPath(), 0700); err != nil {
+		return nil, util.HandleError("Failed to create certificate authority directory", err)
+	}
+	if err := writeKeyPair(GetCAFilePath(), GetCAKeyFilePath(), der, key); err != nil {
+		return nil, err
+	}
+
+	util.PrintInfoReplace("Certificate authority created at " + GetCAFilePath())
+	return &Authority{Cert: cert, Key: key}, nil
+}
+
+// writeKeyPair stores a certificate and its private key as PEM files
+// Both files are only readable by the current user
+func writeKeyPair(certPath string, keyPath string, certDER []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return util.HandleError("Failed to encode private key", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	if err := util.CreateFile(certPath, certPEM, util.FileOptions{Mode: util.ModePrivate}); err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return util.CreateFile(keyPath, keyPEM, util.FileOptions{Mode: util.ModePrivate})
+}
+
+// parseCertificate decodes a PEM encoded certificate
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("invalid certificate PEM data")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// parsePrivateKey decodes a PEM encoded ECDSA private key
+func parsePrivateKey(keyPEM []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("invalid private key PEM data")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an ECDSA key")
+	}
+	return ecdsaKey, nil
+}
+
+// newSerialNumber generates a random certificate serial number
+func newSerialNumber() (*big.Int, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, util.HandleError("Failed to generate serial number", err)
+	}
+	return serialNumber, nil
+}
@@ -0,0 +1,209 @@
+// Package certs manages Tulip's local certificate authority and the certificates it issues
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Validity period of the issued certificates, the maximum accepted by Apple platforms
+	certValidity = 825 * 24 * time.Hour
+	// Certificates expiring within this period are renewed
+	renewBefore = 30 * 24 * time.Hour
+)
+
+// tlsConfig mirrors the subset of Traefik's dynamic configuration used to load certificates
+type tlsConfig struct {
+	TLS struct {
+		Certificates []tlsCertificate `yaml:"certificates"`
+	} `yaml:"tls"`
+}
+
+// tlsCertificate represents a certificate entry of Traefik's dynamic configuration
+type tlsCertificate struct {
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+}
+
+// GetCertFilePath constructs the full path to the certificate of a project
+func GetCertFilePath(name string) string {
+	return filepath.Join(config.GetProjectCertsDirPath(name), config.CertsCertFile)
+}
+
+// GetKeyFilePath constructs the full path to the private key of a project's certificate
+func GetKeyFilePath(name string) string {
+	return filepath.Join(config.GetProjectCertsDirPath(name), config.CertsKeyFile)
+}
+
+// GetTLSFilePath constructs the full path to the Traefik dynamic configuration of a project
+func GetTLSFilePath(name string) string {
+	return filepath.Join(config.GetCertsConfigDirPath(), name+config.CertsTLSFileExt)
+}
+
+// Ensure makes sure a valid certificate covering the given hostnames exists for a project
+// Returns true if a new certificate was issued
+func Ensure(name string, hostnames []string) (bool, error) {
+	ca, _, err := EnsureCA()
+	if err != nil {
+		return false, err
+	}
+
+	if isValid(ca, name, hostnames) {
+		return false, nil
+	}
+	if err := issue(ca, name, hostnames); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Issue creates a new certificate covering the given hostnames for a project,
+// replacing any existing one
+func Issue(name string, hostnames []string) error {
+	ca, _, err := EnsureCA()
+	if err != nil {
+		return err
+	}
+	return issue(ca, name, hostnames)
+}
+
+// Remove deletes the certificate of a project along with its Traefik dynamic configuration
+// Returns true if there was something to remove
+func Remove(name string) (bool, error) {
+	removed := false
+	for _, p := range []string{GetTLSFilePath(name), config.GetProjectCertsDirPath(name)} {
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.RemoveAll(p); err != nil {
+			return removed, util.HandleError("Failed to remove "+p, err)
+		}
+		removed = true
+	}
+	return removed, nil
+}
+
+// issue signs a new certificate for a project and registers it with Traefik
+func issue(ca *Authority, name string, hostnames []string) error {
+	util.PrintInfo("Issuing certificate for " + name + "..")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return util.HandleError("Failed to generate certificate key", err)
+	}
+
+	serialNumber, err := newSerialNumber()
+	if err != nil {
+		return err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{config.AppName + " development certificate"},
+			CommonName:   hostnames[0],
+		},
+		DNSNames:    certHostnames(hostnames),
+		NotBefore:   time.Now().Add(-time.Hour),
+		NotAfter:    time.Now().Add(certValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, &key.PublicKey, ca.Key)
+	if err != nil {
+		return util.HandleError("Failed to create certificate for "+name, err)
+	}
+
+	// Create project certs directory if it doesn't exist
+	if err := os.MkdirAll(config.GetProjectCertsDirPath(name), 0755); err != nil {
+		return util.HandleError("Failed to create certificate directory", err)
+	}
+	if err := writeKeyPair(GetCertFilePath(name), GetKeyFilePath(name), der, key); err != nil {
+		return err
+	}
+
+	// Let Traefik know about the certificate
+	if err := writeTLSConfig(name); err != nil {
+		return err
+	}
+
+	util.PrintInfoReplace("Certificate issued for " + strings.Join(template.DNSNames, ", "))
+	return nil
+}
+
+// writeTLSConfig creates the Traefik dynamic configuration loading a project's certificate
+// Paths are expressed as seen from inside the proxy container
+func writeTLSConfig(name string) error {
+	tls := tlsConfig{}
+	tls.TLS.Certificates = []tlsCertificate{{
+		CertFile: path.Join(config.CertsMountPath, name, config.CertsCertFile),
+		KeyFile:  path.Join(config.CertsMountPath, name, config.CertsKeyFile),
+	}}
+
+	content, err := yaml.Marshal(tls)
+	if err != nil {
+		return util.HandleError("Failed to generate TLS configuration", err)
+	}
+	return util.CreateFile(GetTLSFilePath(name), content, util.FileOptions{Mode: util.ModePrivate})
+}
+
+// isValid checks if the existing certificate of a project was signed by the CA,
+// covers all the hostnames and isn't about to expire
+func isValid(ca *Authority, name string, hostnames []string) bool {
+	certPEM, err := os.ReadFile(GetCertFilePath(name))
+	if err != nil {
+		return false
+	}
+	if _, err := os.Stat(GetKeyFilePath(name)); err != nil {
+		return false
+	}
+	if _, err := os.Stat(GetTLSFilePath(name)); err != nil {
+		return false
+	}
+
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return false
+	}
+	if cert.CheckSignatureFrom(ca.Cert) != nil {
+		return false
+	}
+	if time.Until(cert.NotAfter) < renewBefore {
+		return false
+	}
+	for _, hostname := range certHostnames(hostnames) {
+		if !slices.Contains(cert.DNSNames, hostname) {
+			return false
+		}
+	}
+	return true
+}
+
+// certHostnames returns the names a certificate must cover: every hostname
+// along with a wildcard covering its subdomains
+func certHostnames(hostnames []string) []string {
+	names := make([]string, 0, len(hostnames)*2)
+	for _, hostname := range hostnames {
+		names = append(names, hostname)
+		if !strings.HasPrefix(hostname, "*.") {
+			names = append(names, "*."+hostname)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
@@ -0,0 +1,255 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+)
+
+// useTempHome keeps the certificate authority and certificates in a temporary directory
+func useTempHome(t *testing.T) {
+	t.Helper()
+	t.Setenv(config.EnvHome, t.TempDir())
+}
+
+// readCertificate parses the certificate issued for a project
+func readCertificate(t *testing.T, name string) *x509.Certificate {
+	t.Helper()
+	certPEM, err := os.ReadFile(GetCertFilePath(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// checkMode fails the test if a file doesn't have the given permissions
+func checkMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != want {
+		t.Errorf("%s mode = %v, want %v", path, info.Mode().Perm(), want)
+	}
+}
+
+func TestEnsureCA(t *testing.T) {
+	useTempHome(t)
+
+	ca, created, err := EnsureCA()
+	if err != nil || !created {
+		t.Fatalf("EnsureCA = %v, %v, want a new authority", created, err)
+	}
+	if !ca.Cert.IsCA || ca.Cert.CheckSignatureFrom(ca.Cert) != nil {
+		t.Error("certificate authority isn't a self-signed CA")
+	}
+	checkMode(t, config.GetCAConfigDirPath(), 0700)
+	checkMode(t, GetCAFilePath(), 0600)
+	checkMode(t, GetCAKeyFilePath(), 0600)
+	if strings.HasPrefix(GetCAKeyFilePath(), config.GetCertsConfigDirPath()+string(filepath.Separator)) {
+		t.Errorf("CA key %s is in the directory mounted in the proxy", GetCAKeyFilePath())
+	}
+
+	reused, created, err := EnsureCA()
+	if err != nil || created {
+		t.Fatalf("second EnsureCA = %v, %v, want the existing authority", created, err)
+	}
+	if reused.Cert.SerialNumber.Cmp(ca.Cert.SerialNumber) != 0 || !reused.Key.Equal(ca.Key) {
+		t.Error("second EnsureCA didn't reuse the existing authority")
+	}
+}
+
+func TestIssue(t *testing.T) {
+	useTempHome(t)
+
+	if err := Issue("demo", []string{"demo.tulip.test", "api.demo.tulip.test"}); err != nil {
+		t.Fatalf("Issue returned %v", err)
+	}
+	ca, err := LoadCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := readCertificate(t, "demo")
+	if err := cert.CheckSignatureFrom(ca.Cert); err != nil {
+		t.Errorf("certificate isn't signed by the CA: %v", err)
+	}
+	want := []string{"*.api.demo.tulip.test", "*.demo.tulip.test", "api.demo.tulip.test", "demo.tulip.test"}
+	if !slices.Equal(cert.DNSNames, want) {
+		t.Errorf("DNSNames = %v, want %v", cert.DNSNames, want)
+	}
+	if cert.Subject.CommonName != "demo.tulip.test" {
+		t.Errorf("CommonName = %s", cert.Subject.CommonName)
+	}
+	checkMode(t, GetKeyFilePath("demo"), 0600)
+
+	tls, err := os.ReadFile(GetTLSFilePath("demo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{config.CertsMountPath + "demo/cert.pem", config.CertsMountPath + "demo/key.pem"} {
+		if !strings.Contains(string(tls), want) {
+			t.Errorf("TLS configuration doesn't refer to %s:\n%s", want, tls)
+		}
+	}
+
+	if removed, err := Remove("demo"); err != nil || !removed {
+		t.Errorf("Remove = %v, %v, want true, nil", removed, err)
+	}
+	if _, err := os.Stat(GetTLSFilePath("demo")); !os.IsNotExist(err) {
+		t.Errorf("TLS configuration wasn't removed: %v", err)
+	}
+}
+
+func TestEnsure(t *testing.T) {
+	useTempHome(t)
+	hostnames := []string{"demo.tulip.test"}
+
+	if issued, err := Ensure("demo", hostnames); err != nil || !issued {
+		t.Fatalf("Ensure = %v, %v, want a new certificate", issued, err)
+	}
+	serial := readCertificate(t, "demo").SerialNumber
+	if issued, err := Ensure("demo", hostnames); err != nil || issued {
+		t.Errorf("second Ensure = %v, %v, want the existing certificate", issued, err)
+	}
+	if readCertificate(t, "demo").SerialNumber.Cmp(serial) != 0 {
+		t.Error("second Ensure replaced the certificate")
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	useTempHome(t)
+	ca, _, err := EnsureCA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA, err := newTestAuthority()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostnames := []string{"demo.tulip.test"}
+
+	tests := []struct {
+		name      string
+		signer    *Authority
+		notAfter  time.Time
+		dnsNames  []string
+		hostnames []string
+		want      bool
+	}{
+		{"valid", ca, time.Now().Add(certValidity), certHostnames(hostnames), hostnames, true},
+		{"expires soon", ca, time.Now().Add(renewBefore / 2), certHostnames(hostnames), hostnames, false},
+		{"expired", ca, time.Now().Add(-time.Minute), certHostnames(hostnames), hostnames, false},
+		{"missing hostname", ca, time.Now().Add(certValidity), certHostnames(hostnames), []string{"demo.tulip.test", "api.tulip.test"}, false},
+		{"missing wildcard", ca, time.Now().Add(certValidity), hostnames, hostnames, false},
+		{"signed by another CA", otherCA, time.Now().Add(certValidity), certHostnames(hostnames), hostnames, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			writeTestCertificate(t, test.signer, "demo", test.dnsNames, test.notAfter)
+			if got := isValid(ca, "demo", test.hostnames); got != test.want {
+				t.Errorf("isValid = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	t.Run("missing TLS configuration", func(t *testing.T) {
+		writeTestCertificate(t, ca, "demo", certHostnames(hostnames), time.Now().Add(certValidity))
+		if err := os.Remove(GetTLSFilePath("demo")); err != nil {
+			t.Fatal(err)
+		}
+		if isValid(ca, "demo", hostnames) {
+			t.Error("isValid = true without the TLS configuration")
+		}
+	})
+	t.Run("missing certificate", func(t *testing.T) {
+		if isValid(ca, "other", hostnames) {
+			t.Error("isValid = true without a certificate")
+		}
+	})
+}
+
+func TestCertHostnames(t *testing.T) {
+	tests := []struct {
+		hostnames []string
+		want      []string
+	}{
+		{[]string{"demo.tulip.test"}, []string{"*.demo.tulip.test", "demo.tulip.test"}},
+		{[]string{"*.demo.tulip.test"}, []string{"*.demo.tulip.test"}},
+		{[]string{"demo.tulip.test", "*.demo.tulip.test", "demo.tulip.test"}, []string{"*.demo.tulip.test", "demo.tulip.test"}},
+		{[]string{"b.tulip.test", "a.tulip.test"}, []string{"*.a.tulip.test", "*.b.tulip.test", "a.tulip.test", "b.tulip.test"}},
+	}
+	for _, test := range tests {
+		if got := certHostnames(test.hostnames); !slices.Equal(got, test.want) {
+			t.Errorf("certHostnames(%v) = %v, want %v", test.hostnames, got, test.want)
+		}
+	}
+}
+
+// newTestAuthority creates a certificate authority that is only kept in memory
+func newTestAuthority() (*Authority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &Authority{Cert: cert, Key: key}, nil
+}
+
+// writeTestCertificate stores a certificate for a project as issue does, with the given names and expiry
+func writeTestCertificate(t *testing.T, signer *Authority, name string, dnsNames []string, notAfter time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer.Cert, &key.PublicKey, signer.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(config.GetProjectCertsDirPath(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeKeyPair(GetCertFilePath(name), GetKeyFilePath(name), der, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeTLSConfig(name); err != nil {
+		t.Fatal(err)
+	}
+}
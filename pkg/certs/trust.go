@@ -0,0 +1,251 @@
+// Package certs provides functionality for installing the root certificate into the trust stores of the system
+package certs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+const (
+	// Name under which the root certificate is installed in the trust stores
+	trustName = "tulip-rootCA"
+	// Nickname of the root certificate in NSS databases
+	trustNickname = config.AppName + " root CA"
+)
+
+// systemStore describes the layout of a Linux distribution's system trust store
+type systemStore struct {
+	Name      string   // Human-readable name of the store
+	AnchorDir string   // Directory holding the extra trusted certificates
+	FileExt   string   // Extension expected by the update command
+	UpdateCmd []string // Command regenerating the trust bundle
+}
+
+// Known system trust store layouts, in order of detection
+var systemStores = []systemStore{
+	{"Debian/Ubuntu", "/usr/local/share/ca-certificates", ".crt", []string{"update-ca-certificates"}},
+	{"Fedora/RHEL", "/etc/pki/ca-trust/source/anchors", ".pem", []string{"update-ca-trust", "extract"}},
+	{"Arch Linux", "/etc/ca-certificates/trust-source/anchors", ".crt", []string{"trust", "extract-compat"}},
+	{"openSUSE", "/usr/share/pki/trust/anchors", ".pem", []string{"update-ca-certificates"}},
+}
+
+// Locations of the NSS databases used by browsers, relative to the home directory
+var nssDatabaseGlobs = []string{
+	".pki/nssdb",
+	".mozilla/firefox/*",
+	"snap/firefox/common/.mozilla/firefox/*",
+	"snap/chromium/current/.pki/nssdb",
+}
+
+// Trust installs the root certificate into the system trust store and the NSS databases
+// In dry-run mode, the files that would be touched are listed but nothing is changed
+func Trust(dryRun bool) error {
+	if runtime.GOOS != "linux" {
+		return util.HandleError("Installing the certificate authority is only supported on Linux", nil)
+	}
+
+	if _, err := os.Stat(GetCAFilePath()); err != nil {
+		return util.HandleError("Certificate authority not found, run 'tulip certs ca' first", err)
+	}
+
+	if err := trustSystem(dryRun); err != nil {
+		return err
+	}
+	return trustNSS(dryRun)
+}
+
+// Untrust removes the root certificate from the system trust store and the NSS databases
+// In dry-run mode, the files that would be touched are listed but nothing is changed
+func Untrust(dryRun bool) error {
+	if runtime.GOOS != "linux" {
+		return util.HandleError("Removing the certificate authority is only supported on Linux", nil)
+	}
+
+	if err := untrustSystem(dryRun); err != nil {
+		return err
+	}
+	return untrustNSS(dryRun)
+}
+
+// trustSystem copies the root certificate to the system anchors and regenerates the trust bundle
+func trustSystem(dryRun bool) error {
+	store, found := detectSystemStore()
+	if !found {
+		util.PrintWarning("No supported system trust store detected, skipping")
+		return nil
+	}
+
+	anchorPath := filepath.Join(store.AnchorDir, trustName+store.FileExt)
+	if dryRun {
+		util.PrintInfo("Would write " + anchorPath)
+		util.PrintInfo("Would run " + strings.Join(store.UpdateCmd, " "))
+		return nil
+	}
+
+	util.PrintInfo("Installing certificate authority into the " + store.Name + " system trust store..")
+	if err := runPrivileged("install", "-m", "0644", GetCAFilePath(), anchorPath); err != nil {
+		return util.HandleError("Failed to copy certificate authority to "+anchorPath, err)
+	}
+	if err := runPrivileged(store.UpdateCmd...); err != nil {
+		return util.HandleError("Failed to update the system trust store", err)
+	}
+	util.PrintInfoReplace("Certificate authority installed into the " + store.Name + " system trust store")
+	return nil
+}
+
+// untrustSystem removes the root certificate from the system anchors and regenerates the trust bundle
+func untrustSystem(dryRun bool) error {
+	store, found := detectSystemStore()
+	if !found {
+		util.PrintWarning("No supported system trust store detected, skipping")
+		return nil
+	}
+
+	anchorPath := filepath.Join(store.AnchorDir, trustName+store.FileExt)
+	if _, err := os.Stat(anchorPath); os.IsNotExist(err) {
+		util.PrintWarning("Certificate authority is not installed in the " + store.Name + " system trust store")
+		return nil
+	}
+	if dryRun {
+		util.PrintInfo("Would remove " + anchorPath)
+		util.PrintInfo("Would run " + strings.Join(store.UpdateCmd, " "))
+		return nil
+	}
+
+	util.PrintInfo("Removing certificate authority from the " + store.Name + " system trust store..")
+	if err := runPrivileged("rm", "-f", anchorPath); err != nil {
+		return util.HandleError("Failed to remove "+anchorPath, err)
+	}
+	if err := runPrivileged(store.UpdateCmd...); err != nil {
+		return util.HandleError("Failed to update the system trust store", err)
+	}
+	util.PrintInfoReplace("Certificate authority removed from the " + store.Name + " system trust store")
+	return nil
+}
+
+// trustNSS adds the root certificate to every NSS database found in the home directory
+func trustNSS(dryRun bool) error {
+	databases, ok := detectNSSDatabases()
+	if !ok {
+		return nil
+	}
+
+	for _, database := range databases {
+		if dryRun {
+			util.PrintInfo("Would update " + filepath.Join(database, "cert9.db"))
+			continue
+		}
+
+		util.PrintInfo("Installing certificate authority into " + database + "..")
+		cmd := exec.Command("certutil", "-A", "-d", "sql:"+database, "-t", "C,,", "-n", trustNickname, "-i", GetCAFilePath())
+		if err := runCmd(cmd); err != nil {
+			return util.HandleError("Failed to update NSS database "+database, err)
+		}
+		util.PrintInfoReplace("Certificate authority installed into " + database)
+	}
+	return nil
+}
+
+// untrustNSS deletes the root certificate from every NSS database found in the home directory
+func untrustNSS(dryRun bool) error {
+	databases, ok := detectNSSDatabases()
+	if !ok {
+		return nil
+	}
+
+	for _, database := range databases {
+		// Skip databases that don't contain the certificate
+		if exec.Command("certutil", "-L", "-d", "sql:"+database, "-n", trustNickname).Run() != nil {
+			continue
+		}
+		if dryRun {
+			util.PrintInfo("Would update " + filepath.Join(database, "cert9.db"))
+			continue
+		}
+
+		util.PrintInfo("Removing certificate authority from " + database + "..")
+		cmd := exec.Command("certutil", "-D", "-d", "sql:"+database, "-n", trustNickname)
+		if err := runCmd(cmd); err != nil {
+			return util.HandleError("Failed to update NSS database "+database, err)
+		}
+		util.PrintInfoReplace("Certificate authority removed from " + database)
+	}
+	return nil
+}
+
+// detectSystemStore returns the first system trust store layout present on the machine
+func detectSystemStore() (systemStore, bool) {
+	for _, store := range systemStores {
+		if info, err := os.Stat(store.AnchorDir); err != nil || !info.IsDir() {
+			continue
+		}
+		if _, err := exec.LookPath(store.UpdateCmd[0]); err != nil {
+			continue
+		}
+		return store, true
+	}
+	return systemStore{}, false
+}
+
+// detectNSSDatabases returns the NSS databases found in the home directory
+// Returns false if there are none or if they can't be updated
+func detectNSSDatabases() ([]string, bool) {
+	homeDir, err := config.GetUserHomeDir()
+	if err != nil {
+		return nil, false
+	}
+
+	databases := make([]string, 0)
+	for _, pattern := range nssDatabaseGlobs {
+		matches, _ := filepath.Glob(filepath.Join(homeDir, pattern))
+		for _, match := range matches {
+			// Only SQL databases are supported by recent versions of NSS
+			if _, err := os.Stat(filepath.Join(match, "cert9.db")); err == nil {
+				databases = append(databases, match)
+			}
+		}
+	}
+
+	if len(databases) == 0 {
+		util.PrintWarning("No NSS database detected, skipping")
+		return nil, false
+	}
+	if _, err := exec.LookPath("certutil"); err != nil {
+		util.PrintWarning("Found NSS databases but certutil is not installed, skipping")
+		util.PrintWarning("Install the NSS tools (libnss3-tools or nss-tools) to trust the certificate authority in browsers")
+		return nil, false
+	}
+	return databases, true
+}
+
+// runPrivileged runs a command as root, escalating through sudo when needed
+func runPrivileged(cmdArgs ...string) error {
+	if os.Geteuid() != 0 {
+		cmdArgs = append([]string{"sudo"}, cmdArgs...)
+	}
+	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
+	cmd.Stdin = os.Stdin
+	return runCmd(cmd)
+}
+
+// runCmd runs a command and includes its error output in the returned error
+func runCmd(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
+			return fmt.Errorf("%w\n%s", err, errMsg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Package certs implements the certs command functionality
+package certs
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/certs"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// CACmd represents the certs ca command
+// It creates the local certificate authority if it doesn't exist yet
+var CACmd = &cobra.Command{
+	Use:   "ca",
+	Short: "Create the local certificate authority",
+	Long:  `Create Tulip's local root certificate authority if it doesn't exist yet and print its location.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, created, err := certs.EnsureCA()
+		if err != nil {
+			return err
+		}
+		if !created {
+			util.PrintWarning("Certificate authority already exists")
+		}
+		util.AddResult("certificate", certs.GetCAFilePath())
+		util.PrintSuccess("Root certificate: " + certs.GetCAFilePath())
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(CACmd)
+}
@@ -0,0 +1,13 @@
+// Package certs implements the certificate-related commands for managing Tulip's local certificate authority
+package certs
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the base certs command
+var Cmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Manage Tulip's local certificates",
+	Long:  `Commands for creating Tulip's local certificate authority and issuing certificates for projects.`,
+}
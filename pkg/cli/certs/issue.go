@@ -0,0 +1,30 @@
+// Package certs implements the certs command functionality
+package certs
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/certs"
+	"github.com/pierrestoffe/tulip/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+// IssueCmd represents the certs issue command
+// It issues a new certificate for the hostnames of a project
+var IssueCmd = &cobra.Command{
+	Use:   "issue",
+	Short: "Issue a certificate for the project",
+	Long:  `Issue a new certificate covering the hostnames of the project found in the current directory or any of its parents.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := flags.ResolveProject(cmd)
+		if err != nil {
+			return err
+		}
+
+		return certs.Issue(p.Manifest.Name, p.Manifest.Hostnames)
+	},
+}
+
+func init() {
+	flags.AddProject(IssueCmd)
+	Cmd.AddCommand(IssueCmd)
+}
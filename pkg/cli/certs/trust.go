@@ -0,0 +1,28 @@
+// Package certs implements the certs command functionality
+package certs
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/certs"
+	"github.com/spf13/cobra"
+)
+
+// trustDryRun lists the files that would be touched without changing anything
+var trustDryRun bool
+
+// TrustCmd represents the certs trust command
+// It installs the root certificate into the system trust store and NSS databases
+var TrustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "Trust the local certificate authority",
+	Long: `Install Tulip's root certificate into the Linux system trust store and the NSS databases
+used by browsers, so that project certificates are trusted. Requires sudo for the system store.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return certs.Trust(trustDryRun)
+	},
+}
+
+func init() {
+	TrustCmd.Flags().BoolVar(&trustDryRun, "dry-run", false, "List the files that would be touched without changing them")
+	Cmd.AddCommand(TrustCmd)
+}
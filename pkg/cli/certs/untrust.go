@@ -0,0 +1,28 @@
+// Package certs implements the certs command functionality
+package certs
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/certs"
+	"github.com/spf13/cobra"
+)
+
+// untrustDryRun lists the files that would be touched without changing anything
+var untrustDryRun bool
+
+// UntrustCmd represents the certs untrust command
+// It removes the root certificate from the system trust store and NSS databases
+var UntrustCmd = &cobra.Command{
+	Use:   "untrust",
+	Short: "Stop trusting the local certificate authority",
+	Long: `Remove Tulip's root certificate from the Linux system trust store and the NSS databases
+used by browsers. Requires sudo for the system store.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return certs.Untrust(untrustDryRun)
+	},
+}
+
+func init() {
+	UntrustCmd.Flags().BoolVar(&untrustDryRun, "dry-run", false, "List the files that would be touched without changing them")
+	Cmd.AddCommand(UntrustCmd)
+}
@@ -2,9 +2,25 @@
 package cli
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/cli/certs"
+	configCmd "github.com/pierrestoffe/tulip/pkg/cli/config"
+	"github.com/pierrestoffe/tulip/pkg/cli/dns"
+	"github.com/pierrestoffe/tulip/pkg/cli/flags"
+	"github.com/pierrestoffe/tulip/pkg/cli/hosts"
 	"github.com/pierrestoffe/tulip/pkg/cli/initialize"
+	"github.com/pierrestoffe/tulip/pkg/cli/pause"
 	"github.com/pierrestoffe/tulip/pkg/cli/proxy"
+	"github.com/pierrestoffe/tulip/pkg/cli/remove"
+	"github.com/pierrestoffe/tulip/pkg/cli/render"
+	"github.com/pierrestoffe/tulip/pkg/cli/restart"
 	"github.com/pierrestoffe/tulip/pkg/cli/start"
+	"github.com/pierrestoffe/tulip/pkg/cli/stop"
+	"github.com/pierrestoffe/tulip/pkg/cli/templates"
+	"github.com/pierrestoffe/tulip/pkg/cli/unpause"
+	"github.com/pierrestoffe/tulip/pkg/config"
 	"github.com/pierrestoffe/tulip/pkg/setup"
 	"github.com/pierrestoffe/tulip/pkg/util"
 	"github.com/spf13/cobra"
@@ -15,29 +31,148 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "tulip",
 	Short: "Tulip description",
-	Long:  `Long Tulip description`,
+	Long: `Long Tulip description
+
+Exit codes:
+  0  Command succeeded
+  1  Command failed for any other reason
+  2  Flags or arguments are invalid
+  3  Tulip isn't initialized, run 'tulip init'
+  4  Configuration file can't be read or is invalid
+  5  Container runtime isn't installed or isn't running
+  6  Port required by the proxy is already in use
+  7  Generated files don't match the configuration, run 'tulip render'
+
+Configuration values are taken from the defaults, then the configuration file (or --config),
+then environment variables such as TULIP_PROXY_HTTPPORT, then --set flags.
+Run 'tulip config list --show-origin' to see where each value comes from. The proxy files are
+generated by 'tulip init' and 'tulip render', so values used in them only take effect once
+rendered: the proxy refuses to start while its files don't match the effective configuration.`,
+	SilenceErrors: true, // Errors are printed once by Execute
+	SilenceUsage:  true, // Usage errors point to --help instead
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := flags.ApplyOutput(cmd); err != nil {
+			return err
+		}
+		// Command groups only display their help
+		if cmd.HasSubCommands() {
+			return nil
+		}
+		// Every path depends on Tulip's directories, including those of the commands that don't require setup
+		if _, err := config.ResolveDirs(); err != nil {
+			return err
+		}
+		if err := flags.ApplyConfig(cmd); err != nil {
+			return err
+		}
+		return ValidateSetup(commandName(cmd))
+	},
 }
 
-// Execute runs the root command and handles any errors that occur
+// Commands that can run before Tulip is set up, identified by their path without the application name
+var setupOptional = map[string]bool{
+	"init":            true, // Sets Tulip up
+	"help":            true, // Cobra's help command
+	"completion":      true, // Cobra's shell completion commands
+	"dns serve":       true, // Only reads the configuration, which has defaults
+	"hosts list":      true, // Only reads the hosts file
+	"hosts clean":     true, // Only edits the hosts file
+	"certs trust":     true, // Reports a missing certificate authority itself
+	"certs untrust":   true, // Removes whatever was installed
+	"config validate": true, // Reports a missing configuration file itself
+	"templates":       true, // Templates can be customized before running init
+}
+
+// Execute runs the root command and prints the error it failed with, if any
+// Returns the error so that the caller can exit with the matching code
 func Execute() error {
-	if err := rootCmd.Execute(); err != nil {
-		return util.PrintErrorE(err)
+	err := rootCmd.Execute()
+	if err != nil {
+		util.PrintError(err.Error())
 	}
-	return nil
+	util.PrintResult(err)
+	return err
 }
 
 // ValidateSetup checks if Tulip is properly set up before running commands
-// It skips validation for the "init" command since setup isn't required for initialization
+// It skips validation for the commands that don't require setup, such as "init"
 func ValidateSetup(cmdName string) error {
-	if cmdName == "init" {
+	if setupOptional[cmdName] {
+		return nil
+	}
+	// Groups such as "completion" and "templates" don't require setup for any of their subcommands
+	if group, _, found := strings.Cut(cmdName, " "); found && setupOptional[group] {
 		return nil
 	}
 	return setup.Ensure()
 }
 
+// commandName returns the path of a command without the application name, e.g. "proxy start"
+func commandName(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+// usageError reports invalid flags or arguments given to a command
+func usageError(cmd *cobra.Command, err error) error {
+	return util.NewError(util.ErrUsage, "Invalid usage", err, "Run '"+cmd.CommandPath()+" --help' for usage")
+}
+
+// validateUsage makes a command and its subcommands report invalid arguments as usage errors
+// Command groups become runnable so that cobra validates their arguments, which can only be unknown subcommands
+func validateUsage(cmd *cobra.Command) {
+	if cmd.HasSubCommands() && !cmd.Runnable() {
+		cmd.Args = unknownSubcommand
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		}
+	} else if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				return usageError(cmd, err)
+			}
+			return nil
+		}
+	}
+	for _, subcommand := range cmd.Commands() {
+		validateUsage(subcommand)
+	}
+}
+
+// unknownSubcommand rejects the arguments given to a command group, suggesting the closest subcommand
+func unknownSubcommand(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	err := fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath())
+	if suggestions := cmd.SuggestionsFor(args[0]); len(suggestions) > 0 {
+		return util.NewError(util.ErrUsage, "Invalid usage", err, "Did you mean '"+cmd.CommandPath()+" "+suggestions[0]+"'?")
+	}
+	return usageError(cmd, err)
+}
+
 // init adds all child commands to the root command
 func init() {
+	flags.AddOutput(rootCmd)
+	flags.AddConfig(rootCmd)
+	rootCmd.SetFlagErrorFunc(usageError)
+
 	rootCmd.AddCommand(proxy.Cmd)
 	rootCmd.AddCommand(initialize.Cmd)
 	rootCmd.AddCommand(start.Cmd)
+	rootCmd.AddCommand(stop.Cmd)
+	rootCmd.AddCommand(restart.Cmd)
+	rootCmd.AddCommand(pause.Cmd)
+	rootCmd.AddCommand(unpause.Cmd)
+	rootCmd.AddCommand(remove.Cmd)
+	rootCmd.AddCommand(certs.Cmd)
+	rootCmd.AddCommand(dns.Cmd)
+	rootCmd.AddCommand(hosts.Cmd)
+	rootCmd.AddCommand(configCmd.Cmd)
+	rootCmd.AddCommand(templates.Cmd)
+	rootCmd.AddCommand(render.Cmd)
+
+	// Cobra adds its own commands when executing, they're needed now to validate their usage too
+	rootCmd.InitDefaultHelpCmd()
+	rootCmd.InitDefaultCompletionCmd()
+	validateUsage(rootCmd)
 }
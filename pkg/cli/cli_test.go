@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// Positional arguments accepted by the commands that take some, the others take none
+var acceptedArgs = map[string]int{
+	"config get":      1,
+	"config set":      2,
+	"config validate": 1,
+	"templates show":  1,
+}
+
+// Commands accepting any number of arguments
+var anyArgs = map[string]bool{
+	"help": true, // Takes the path of the command to describe
+}
+
+// allCommands returns the commands below cmd, excluding hidden ones such as cobra's completion handlers
+func allCommands(cmd *cobra.Command) []*cobra.Command {
+	var commands []*cobra.Command
+	for _, subcommand := range cmd.Commands() {
+		if subcommand.Hidden {
+			continue
+		}
+		commands = append(commands, subcommand)
+		commands = append(commands, allCommands(subcommand)...)
+	}
+	return commands
+}
+
+func TestUnexpectedArgument(t *testing.T) {
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetOut(nil)
+	defer rootCmd.SetErr(nil)
+
+	for _, cmd := range allCommands(rootCmd) {
+		name := commandName(cmd)
+		if anyArgs[name] {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			args := strings.Fields(name)
+			for range acceptedArgs[name] {
+				args = append(args, "value")
+			}
+			rootCmd.SetArgs(append(args, "unexpected"))
+			if code := util.ExitCode(rootCmd.Execute()); code != 2 {
+				t.Errorf("exit code = %d, want 2", code)
+			}
+		})
+	}
+}
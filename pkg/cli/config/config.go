@@ -0,0 +1,13 @@
+// Package config implements the config commands for inspecting, changing and upgrading Tulip's configuration
+package config
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the base config command
+var Cmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage Tulip's configuration",
+	Long:  `Commands for reading, changing and upgrading Tulip's configuration file.`,
+}
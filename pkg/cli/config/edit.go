@@ -0,0 +1,108 @@
+// Package config implements the config edit command
+package config
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// Editor used when neither $VISUAL nor $EDITOR is set
+const defaultEditor = "vi"
+
+// EditCmd represents the config edit command
+// It opens the configuration file in the user's editor and validates it before saving
+var EditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the configuration file",
+	Long: `Open a copy of the configuration file in $VISUAL or $EDITOR. The changes are only saved
+once the edited file is valid, otherwise you're offered to edit it again.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath := config.GetConfigFilePath()
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			return util.NewError(util.ErrNotInitialized, "Failed to read configuration file", err)
+		}
+
+		// Edit a copy so the configuration is never left invalid
+		tmpFile, err := os.CreateTemp("", "tulip-config-*.yml")
+		if err != nil {
+			return util.HandleError("Failed to create temporary file", err)
+		}
+		tmpPath := tmpFile.Name()
+		defer os.Remove(tmpPath)
+		if _, err := tmpFile.Write(content); err != nil {
+			tmpFile.Close()
+			return util.HandleError("Failed to write temporary file", err)
+		}
+		tmpFile.Close()
+
+		for {
+			if err := runEditor(tmpPath); err != nil {
+				return err
+			}
+			edited, err := os.ReadFile(tmpPath)
+			if err != nil {
+				return util.HandleError("Failed to read edited file", err)
+			}
+			if bytes.Equal(edited, content) {
+				util.PrintWarning("Configuration unchanged")
+				return nil
+			}
+
+			// Save the changes once they're valid
+			_, parseErr := config.Parse(edited)
+			if parseErr == nil {
+				if err := util.UpdateFileAtomic(configPath, edited); err != nil {
+					return err
+				}
+				util.PrintSuccess("Configuration saved to " + configPath)
+				return nil
+			}
+
+			if !util.IsInteractive() {
+				return util.NewError(util.ErrConfigInvalid, "Configuration left unchanged", parseErr)
+			}
+			util.PrintWarning(parseErr.Error())
+			retry, err := util.Confirm("Edit the file again? Your changes are discarded otherwise.")
+			if err != nil {
+				return err
+			}
+			if !retry {
+				return util.NewError(util.ErrConfigInvalid, "Configuration left unchanged", parseErr)
+			}
+		}
+	},
+}
+
+// runEditor opens a file in the user's editor and waits for it to exit
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = defaultEditor
+	}
+
+	// The editor may come with arguments, e.g. "code --wait"
+	editorArgs := strings.Fields(editor)
+	cmd := exec.Command(editorArgs[0], append(editorArgs[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return util.HandleError("Failed to run editor "+editor, err)
+	}
+	return nil
+}
+
+func init() {
+	Cmd.AddCommand(EditCmd)
+}
@@ -0,0 +1,36 @@
+// Package config implements the config get command
+package config
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// GetCmd represents the config get command
+// It prints the effective value of a configuration key
+var GetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Long:  `Print the effective value of a configuration key such as proxy.httpPort.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Get configuration
+		cfg, err := config.Get()
+		if err != nil {
+			return err
+		}
+
+		value, err := config.GetValue(cfg, args[0])
+		if err != nil {
+			return err
+		}
+		util.AddResult(args[0], value)
+		util.PrintOutput(value)
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(GetCmd)
+}
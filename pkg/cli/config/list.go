@@ -0,0 +1,54 @@
+// Package config implements the config list command
+package config
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// listShowOrigin tells whether the origin of each value should be printed
+var listShowOrigin bool
+
+// ListCmd represents the config list command
+// It prints every configuration key with its effective value
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the configuration values",
+	Long: `List every configuration key with its effective value, including defaults missing from the file.
+Use --show-origin to print where each value comes from: default, file, env or flag.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Get configuration
+		cfg, err := config.Get()
+		if err != nil {
+			return err
+		}
+
+		values := make(map[string]string)
+		origins := make(map[string]string)
+		for _, key := range config.Keys() {
+			value, err := config.GetValue(cfg, key)
+			if err != nil {
+				return err
+			}
+			values[key] = value
+			if !listShowOrigin {
+				util.PrintOutput(key + "=" + value)
+				continue
+			}
+			origins[key] = config.GetOrigin(key).String()
+			util.PrintOutput(origins[key] + "\t" + key + "=" + value)
+		}
+		util.AddResult("config", values)
+		if listShowOrigin {
+			util.AddResult("origins", origins)
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(ListCmd)
+	ListCmd.Flags().BoolVar(&listShowOrigin, "show-origin", false, "Print where each value comes from")
+}
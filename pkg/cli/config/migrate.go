@@ -0,0 +1,67 @@
+// Package config implements the config migrate command
+package config
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// migrateDryRun tells whether the migration should only be printed
+var migrateDryRun bool
+
+// MigrateCmd represents the config migrate command
+// It upgrades the configuration file to the current schema version
+var MigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the configuration file to the current schema version",
+	Long: `Upgrade the configuration file to the current schema version, keeping a backup of the previous file.
+Older files are also upgraded automatically the first time they're loaded. Use --dry-run to print the
+changes without writing them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath := config.GetConfigFilePath()
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			return util.NewError(util.ErrNotInitialized, "Failed to read configuration file", err)
+		}
+
+		migrated, applied, err := config.Migrate(content)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			util.PrintSuccess("Configuration is already at version " + config.ConfigVersion)
+			return nil
+		}
+
+		for _, migration := range applied {
+			util.PrintInfo("Version " + migration.From + " to " + migration.To + ": " + migration.Description)
+		}
+		diff := util.Diff(configPath, configPath, string(content), string(migrated))
+		util.AddResult("diff", diff)
+
+		if migrateDryRun {
+			util.PrintEmpty()
+			for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+				util.PrintOutput(line)
+			}
+			return nil
+		}
+
+		// Loading the configuration migrates the file and validates the result
+		if _, err := config.Initialize(); err != nil {
+			return err
+		}
+		util.PrintSuccess("Previous configuration saved to " + config.GetConfigBackupFilePath(configPath, applied[0].From))
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(MigrateCmd)
+	MigrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Print the changes without writing them")
+}
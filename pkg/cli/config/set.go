@@ -0,0 +1,44 @@
+// Package config implements the config set command
+package config
+
+import (
+	"os"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// SetCmd represents the config set command
+// It changes a value in the configuration file
+var SetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a configuration value",
+	Long: `Change the value of a configuration key such as proxy.httpPort in the configuration file.
+The value is checked before the file is written, and comments in the file are kept.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+
+		configPath := config.GetConfigFilePath()
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			return util.NewError(util.ErrNotInitialized, "Failed to read configuration file", err)
+		}
+
+		changed, err := config.SetValue(content, key, value)
+		if err != nil {
+			return err
+		}
+		if err := util.UpdateFileAtomic(configPath, changed); err != nil {
+			return err
+		}
+
+		util.PrintSuccess("Set " + key + " to " + value)
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(SetCmd)
+}
@@ -0,0 +1,70 @@
+// Package config implements the config validate command
+package config
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// ValidateCmd represents the config validate command
+// It reports every problem found in a configuration file
+var ValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check the configuration file for invalid values",
+	Long: `Check the configuration file, or the given file, and report every invalid value along with its line.
+Unknown keys and sockets that don't exist on this machine are reported as warnings.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath := config.GetConfigFilePath()
+		if len(args) > 0 {
+			configPath = args[0]
+		}
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			return util.NewError(util.ErrNotInitialized, "Failed to read configuration file", err)
+		}
+
+		// Files written for older schema versions are checked once upgraded
+		migrated, _, err := config.Migrate(content)
+		if err != nil {
+			return err
+		}
+		if string(migrated) != string(content) {
+			util.PrintWarning("Configuration file uses an older schema version, line numbers are those of the upgraded file")
+		}
+
+		_, problems, err := config.Check(migrated)
+		if err != nil {
+			return err
+		}
+
+		errors := make([]config.Problem, 0, len(problems))
+		for _, problem := range problems {
+			if problem.Warning {
+				util.PrintWarning(problem.String())
+				continue
+			}
+			errors = append(errors, problem)
+		}
+		util.AddResult("problems", problems)
+
+		if len(errors) > 0 {
+			return util.NewError(util.ErrConfigInvalid, "Invalid configuration in "+configPath,
+				&config.ValidationError{Problems: errors})
+		}
+		if len(problems) > 0 {
+			util.PrintSuccess("Configuration is valid, with " + strconv.Itoa(len(problems)) + " warning(s)")
+			return nil
+		}
+		util.PrintSuccess("Configuration is valid")
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(ValidateCmd)
+}
@@ -0,0 +1,13 @@
+// Package dns implements the commands for managing Tulip's built-in DNS resolver
+package dns
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the base dns command
+var Cmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Manage the Tulip DNS resolver",
+	Long:  `Commands for running the DNS resolver that points project hostnames to the local machine.`,
+}
@@ -0,0 +1,62 @@
+// Package dns implements the dns command functionality
+package dns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/dns"
+	proxyDNS "github.com/pierrestoffe/tulip/pkg/proxy/dns"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// ServeCmd represents the dns serve command
+// It runs the DNS resolver in the foreground until interrupted
+var ServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the DNS resolver in the foreground",
+	Long: `Run the DNS resolver in the foreground. It answers queries for the configured TLD with 127.0.0.1
+and forwards or refuses everything else. The proxy normally runs it in the background.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Get configuration
+		cfg, err := config.Get()
+		if err != nil {
+			return err
+		}
+
+		// Record this process as the resolver, so that it can be stopped
+		release, err := proxyDNS.Claim()
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		server := &dns.Server{
+			Addr:     net.JoinHostPort("127.0.0.1", cfg.DNS.Port),
+			TLD:      cfg.DNS.TLD,
+			Upstream: cfg.DNS.Upstream,
+		}
+		util.PrintInfo("Answering queries for *." + server.TLD + " on " + server.Addr)
+		if err := server.ListenAndServe(ctx); err != nil {
+			if errors.Is(err, syscall.EADDRINUSE) {
+				return util.NewError(util.ErrPortInUse, "DNS port is already in use: "+cfg.DNS.Port, err)
+			}
+			return util.HandleError("DNS resolver failed", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(ServeCmd)
+}
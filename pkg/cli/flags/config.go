@@ -0,0 +1,45 @@
+// Package flags provides the global flags selecting the configuration file and overriding its values
+package flags
+
+import (
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// Names of the flags used to select the configuration file and override its values
+const (
+	configFlag = "config"
+	setFlag    = "set"
+)
+
+// AddConfig registers the global configuration flags on the root command
+func AddConfig(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(configFlag, "", "Path of an alternate configuration file")
+	cmd.PersistentFlags().StringArray(setFlag, nil, "Override a configuration value for this run, e.g. --set proxy.httpPort=8080 (can be repeated)")
+}
+
+// ApplyConfig selects the configuration file and the values overridden by the flags of the command about to run
+func ApplyConfig(cmd *cobra.Command) error {
+	path, err := cmd.Flags().GetString(configFlag)
+	if err != nil {
+		return err
+	}
+	config.SetFilePath(path)
+
+	assignments, err := cmd.Flags().GetStringArray(setFlag)
+	if err != nil {
+		return err
+	}
+	overrides := make(map[string]string)
+	for _, assignment := range assignments {
+		key, value, found := strings.Cut(assignment, "=")
+		if !found {
+			return util.NewError(util.ErrUsage, "Invalid value for --set: "+assignment, nil, "Expected key=value, e.g. proxy.httpPort=8080")
+		}
+		overrides[key] = value
+	}
+	return config.SetOverrides(overrides)
+}
@@ -0,0 +1,59 @@
+// Package flags provides the global flags selecting Tulip's output format and verbosity
+package flags
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// Names of the flags used to select the output format and verbosity
+const (
+	outputFlag  = "output"
+	quietFlag   = "quiet"
+	verboseFlag = "verbose"
+	debugFlag   = "debug"
+)
+
+// AddOutput registers the global output flags on the root command
+func AddOutput(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(outputFlag, "o", util.OutputText, "Output format ("+strings.Join(util.Outputs, " or ")+")")
+	cmd.PersistentFlags().BoolP(quietFlag, "q", false, "Only print requested data, warnings and errors")
+	cmd.PersistentFlags().BoolP(verboseFlag, "v", false, "Also print details such as created files")
+	cmd.PersistentFlags().Bool(debugFlag, false, "Also print debug information such as executed commands")
+	cmd.MarkFlagsMutuallyExclusive(quietFlag, verboseFlag, debugFlag)
+}
+
+// ApplyOutput sets up the printer matching the output flags for the command about to run
+func ApplyOutput(cmd *cobra.Command) error {
+	output, err := cmd.Flags().GetString(outputFlag)
+	if err != nil {
+		return err
+	}
+	printer, err := util.NewPrinter(output, os.Stdout, os.Stderr)
+	if err != nil {
+		return err
+	}
+	util.SetPrinter(printer)
+	util.SetVerbosity(getVerbosity(cmd))
+
+	// Identify events by the command path without the application name
+	util.SetComponent(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "))
+	return nil
+}
+
+// getVerbosity returns the verbosity selected by the flags of a command
+func getVerbosity(cmd *cobra.Command) util.Verbosity {
+	if debug, _ := cmd.Flags().GetBool(debugFlag); debug {
+		return util.VerbosityDebug
+	}
+	if verbose, _ := cmd.Flags().GetBool(verboseFlag); verbose {
+		return util.VerbosityVerbose
+	}
+	if quiet, _ := cmd.Flags().GetBool(quietFlag); quiet {
+		return util.VerbosityQuiet
+	}
+	return util.VerbosityNormal
+}
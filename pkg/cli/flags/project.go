@@ -0,0 +1,34 @@
+// Package flags provides command-line flags shared by several Tulip commands
+package flags
+
+import (
+	"fmt"
+
+	"github.com/pierrestoffe/tulip/pkg/project"
+	"github.com/spf13/cobra"
+)
+
+// Name of the flag used to select a project
+const projectFlag = "project"
+
+// AddProject registers the --project flag on a project-scoped command
+func AddProject(cmd *cobra.Command) {
+	cmd.Flags().StringP(projectFlag, "p", "", "Project name or path (defaults to $"+project.EnvProject+" or the current directory)")
+}
+
+// NoProjectArgs rejects the positional arguments of a project-scoped command, which selects its project with --project
+func NoProjectArgs(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected argument %q, select a project with --%s", args[0], projectFlag)
+	}
+	return nil
+}
+
+// ResolveProject finds the project targeted by a project-scoped command
+func ResolveProject(cmd *cobra.Command) (*project.Project, error) {
+	selector, err := cmd.Flags().GetString(projectFlag)
+	if err != nil {
+		return nil, err
+	}
+	return project.Resolve(selector)
+}
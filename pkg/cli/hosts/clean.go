@@ -0,0 +1,31 @@
+// Package hosts implements the hosts command functionality
+package hosts
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/hosts"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// CleanCmd represents the hosts clean command
+// It removes the Tulip block from the hosts file
+var CleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove Tulip's entries from the hosts file",
+	Long:  `Remove the Tulip block from the hosts file, leaving every other entry untouched.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changed, err := hosts.Clean()
+		if err != nil {
+			return err
+		}
+		if !changed {
+			util.PrintWarning("Hosts file contains no Tulip entries")
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(CleanCmd)
+}
@@ -0,0 +1,14 @@
+// Package hosts implements the commands for managing Tulip's entries in the hosts file
+package hosts
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the base hosts command
+var Cmd = &cobra.Command{
+	Use:   "hosts",
+	Short: "Manage project hostnames in the hosts file",
+	Long: `Commands for keeping a block of project hostnames in the hosts file, as a fallback
+for machines that can't use the Tulip DNS resolver.`,
+}
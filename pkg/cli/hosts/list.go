@@ -0,0 +1,36 @@
+// Package hosts implements the hosts command functionality
+package hosts
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/hosts"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// ListCmd represents the hosts list command
+// It prints the hostnames currently managed by Tulip
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Tulip's entries in the hosts file",
+	Long:  `List the hostnames found in the Tulip block of the hosts file.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hostnames, err := hosts.List()
+		if err != nil {
+			return err
+		}
+		util.AddResult("hostnames", hostnames)
+		if len(hostnames) == 0 {
+			util.PrintWarning("Hosts file contains no Tulip entries")
+			return nil
+		}
+		for _, hostname := range hostnames {
+			util.PrintOutput(hostname)
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(ListCmd)
+}
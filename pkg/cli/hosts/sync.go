@@ -0,0 +1,41 @@
+// Package hosts implements the hosts command functionality
+package hosts
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/hosts"
+	"github.com/pierrestoffe/tulip/pkg/project"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// SyncCmd represents the hosts sync command
+// It writes the hostnames of all known projects to the hosts file
+var SyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Add the hostnames of all known projects to the hosts file",
+	Long:  `Update the Tulip block of the hosts file so that it lists the hostnames of all known projects.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projects, err := project.List()
+		if err != nil {
+			return err
+		}
+		hostnames := make([]string, 0)
+		for _, p := range projects {
+			hostnames = append(hostnames, p.Manifest.Hostnames...)
+		}
+
+		changed, err := hosts.Sync(hostnames)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			util.PrintWarning("Hosts file is already up to date")
+		}
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(SyncCmd)
+}
@@ -2,16 +2,65 @@
 package initialize
 
 import (
+	"strings"
+
 	"github.com/pierrestoffe/tulip/pkg/setup"
+	"github.com/pierrestoffe/tulip/pkg/setup/generated"
 	"github.com/spf13/cobra"
 )
 
+// opts holds the options set by the flags of the init command
+var opts setup.Options
+
+// check tells whether init should only report the generated files that differ
+var check bool
+
+// seedFlags lists the flags seeding configuration values
+var seedFlags = []struct {
+	name   string
+	usage  string
+	target *string
+}{
+	{"http-port", "Port the proxy listens on for HTTP", &opts.Seed.HTTPPort},
+	{"https-port", "Port the proxy listens on for HTTPS", &opts.Seed.HTTPSPort},
+	{"admin-port", "Port of the proxy dashboard", &opts.Seed.AdminPort},
+	{"ssh-port", "Port of the SSH service", &opts.Seed.SSHPort},
+	{"network-name", "Name of the container network shared by the projects", &opts.Seed.NetworkName},
+	{"tld", "Top-level domain of the projects", &opts.Seed.TLD},
+}
+
 // Cmd represents the initialization command
 var Cmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize Tulip",
-	Long:  `Initialize Tulip by creating missing directories and config files in the home directory.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		setup.Initialize()
+	Long: `Initialize Tulip by creating missing directories and config files in the home directory.
+
+When Tulip is already initialized, init asks before overwriting the configuration.
+Use --yes to reinitialize without asking, or --force to also start over from the default
+configuration. Configuration values can be seeded with flags such as --http-port, which are saved
+to the configuration file, unlike environment variables and --set flags.
+
+Generated files changed by hand are kept unless you choose to overwrite them or to merge your
+changes into the new version, which --on-conflict answers in advance. Use --check to list the
+generated files that differ from what Tulip generates without changing anything.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if check {
+			return setup.CheckGenerated()
+		}
+		return setup.Initialize(opts)
 	},
 }
+
+func init() {
+	Cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "Reinitialize from the default configuration without asking")
+	Cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "Reinitialize without asking, keeping the current configuration")
+	Cmd.Flags().BoolVar(&opts.NoStart, "no-start", false, "Don't start the proxy once initialized")
+	Cmd.Flags().StringVar(&opts.OnConflict, "on-conflict", "", "How to handle generated files changed by hand ("+strings.Join(generated.Resolutions, ", ")+"), asks by default")
+	Cmd.Flags().BoolVar(&check, "check", false, "Report the generated files that differ from what Tulip generates, without changing anything")
+	Cmd.MarkFlagsMutuallyExclusive("check", "force")
+	Cmd.MarkFlagsMutuallyExclusive("check", "yes")
+	for _, flag := range seedFlags {
+		Cmd.Flags().StringVar(flag.target, flag.name, "", flag.usage)
+	}
+}
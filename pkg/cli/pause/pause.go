@@ -0,0 +1,30 @@
+// Package pause implements the 'pause' command functionality
+package pause
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/cli/flags"
+	"github.com/pierrestoffe/tulip/pkg/project"
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the pause command
+var Cmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause the project",
+	Long:  `Suspend the containers of the project found in the current directory or any of its parents.`,
+	Args:  flags.NoProjectArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Find the targeted project
+		p, err := flags.ResolveProject(cmd)
+		if err != nil {
+			return err
+		}
+
+		_, err = project.Pause(p)
+		return err
+	},
+}
+
+func init() {
+	flags.AddProject(Cmd)
+}
@@ -3,7 +3,6 @@ package proxy
 
 import (
 	"github.com/pierrestoffe/tulip/pkg/proxy"
-	"github.com/pierrestoffe/tulip/pkg/setup"
 	"github.com/spf13/cobra"
 )
 
@@ -13,12 +12,9 @@ var RestartCmd = &cobra.Command{
 	Use:   "restart",
 	Short: "Restart the Tulip proxy server",
 	Long:  `Restart the Tulip proxy server.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := setup.Ensure(); err != nil {
-			return
-		}
-
-		proxy.Restart()
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return proxy.Restart()
 	},
 }
 
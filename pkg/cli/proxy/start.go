@@ -3,7 +3,6 @@ package proxy
 
 import (
 	"github.com/pierrestoffe/tulip/pkg/proxy"
-	"github.com/pierrestoffe/tulip/pkg/setup"
 	"github.com/spf13/cobra"
 )
 
@@ -13,12 +12,9 @@ var StartCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start the Tulip proxy server",
 	Long:  `Start the Tulip proxy server.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := setup.Ensure(); err != nil {
-			return
-		}
-
-		proxy.Start()
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return proxy.Start()
 	},
 }
 
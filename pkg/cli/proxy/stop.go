@@ -3,7 +3,6 @@ package proxy
 
 import (
 	"github.com/pierrestoffe/tulip/pkg/proxy"
-	"github.com/pierrestoffe/tulip/pkg/setup"
 	"github.com/spf13/cobra"
 )
 
@@ -13,12 +12,9 @@ var StopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop the Tulip proxy server",
 	Long:  `Stop the Tulip proxy server.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := setup.Ensure(); err != nil {
-			return
-		}
-
-		proxy.Stop()
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return proxy.Stop()
 	},
 }
 
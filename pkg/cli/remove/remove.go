@@ -0,0 +1,35 @@
+// Package remove implements the 'remove' command functionality
+package remove
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/cli/flags"
+	"github.com/pierrestoffe/tulip/pkg/project"
+	"github.com/spf13/cobra"
+)
+
+// removeVolumes tells whether the named volumes of the project should be deleted too
+var removeVolumes bool
+
+// Cmd represents the remove command
+var Cmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove the project",
+	Long: `Remove the containers of the project found in the current directory or any of its parents,
+along with the files Tulip generated for it. Named volumes are kept unless --volumes is given.`,
+	Args: flags.NoProjectArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Find the targeted project
+		p, err := flags.ResolveProject(cmd)
+		if err != nil {
+			return err
+		}
+
+		_, err = project.Remove(p, removeVolumes)
+		return err
+	},
+}
+
+func init() {
+	flags.AddProject(Cmd)
+	Cmd.Flags().BoolVar(&removeVolumes, "volumes", false, "Also remove the named volumes of the project")
+}
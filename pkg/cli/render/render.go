@@ -0,0 +1,59 @@
+// Package render implements the 'render' command functionality
+package render
+
+import (
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/setup"
+	"github.com/pierrestoffe/tulip/pkg/setup/generated"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// dryRun tells whether the files should only be printed
+var dryRun bool
+
+// onConflict tells how to handle generated files changed by hand
+var onConflict string
+
+// Cmd represents the render command
+var Cmd = &cobra.Command{
+	Use:   "render",
+	Short: "Generate the proxy and SSH files from the configuration",
+	Long: `Generate the proxy and SSH files from the templates and the effective configuration, including
+environment variables and --set flags. Use --dry-run to print the files without writing them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files, err := setup.Render()
+		if err != nil {
+			return err
+		}
+
+		if !dryRun {
+			if err := setup.Write(files, onConflict); err != nil {
+				return err
+			}
+			util.PrintSuccess("Generated files are up to date")
+			return nil
+		}
+
+		contents := make(map[string]string)
+		for i, file := range files {
+			if i > 0 {
+				util.PrintOutput("")
+			}
+			util.PrintOutput("# " + file.Path)
+			for _, line := range strings.Split(strings.TrimSuffix(string(file.Content), "\n"), "\n") {
+				util.PrintOutput(line)
+			}
+			contents[file.Path] = string(file.Content)
+		}
+		util.AddResult("files", contents)
+		return nil
+	},
+}
+
+func init() {
+	Cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the files without writing them")
+	Cmd.Flags().StringVar(&onConflict, "on-conflict", "", "How to handle generated files changed by hand ("+strings.Join(generated.Resolutions, ", ")+"), asks by default")
+}
@@ -0,0 +1,34 @@
+// Package restart implements the 'restart' command functionality
+package restart
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/cli/flags"
+	"github.com/pierrestoffe/tulip/pkg/project"
+	"github.com/pierrestoffe/tulip/pkg/proxy"
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the restart command
+var Cmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart the project",
+	Long:  `Restart the containers of the project found in the current directory or any of its parents.`,
+	Args:  flags.NoProjectArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Find the targeted project
+		p, err := flags.ResolveProject(cmd)
+		if err != nil {
+			return err
+		}
+		// Ensure proxy service is running
+		if err := proxy.Ensure(); err != nil {
+			return err
+		}
+
+		return project.Restart(p)
+	},
+}
+
+func init() {
+	flags.AddProject(Cmd)
+}
@@ -2,9 +2,9 @@
 package start
 
 import (
+	"github.com/pierrestoffe/tulip/pkg/cli/flags"
 	"github.com/pierrestoffe/tulip/pkg/project"
 	"github.com/pierrestoffe/tulip/pkg/proxy"
-	"github.com/pierrestoffe/tulip/pkg/setup"
 	"github.com/spf13/cobra"
 )
 
@@ -12,17 +12,24 @@ import (
 var Cmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start the project",
-	Long:  `Start the project that is found in the current directory.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Ensure Tulip is properly set up
-		if err := setup.Ensure(); err != nil {
-			return
+	Long:  `Start the project found in the current directory or any of its parents.`,
+	Args:  flags.NoProjectArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Find the targeted project
+		p, err := flags.ResolveProject(cmd)
+		if err != nil {
+			return err
 		}
 		// Ensure proxy service is running
 		if err := proxy.Ensure(); err != nil {
-			return
+			return err
 		}
 
-		project.Start()
+		_, err = project.Start(p)
+		return err
 	},
 }
+
+func init() {
+	flags.AddProject(Cmd)
+}
@@ -0,0 +1,30 @@
+// Package stop implements the 'stop' command functionality
+package stop
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/cli/flags"
+	"github.com/pierrestoffe/tulip/pkg/project"
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the stop command
+var Cmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the project",
+	Long:  `Stop the containers of the project found in the current directory or any of its parents.`,
+	Args:  flags.NoProjectArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Find the targeted project
+		p, err := flags.ResolveProject(cmd)
+		if err != nil {
+			return err
+		}
+
+		_, err = project.Stop(p)
+		return err
+	},
+}
+
+func init() {
+	flags.AddProject(Cmd)
+}
@@ -0,0 +1,46 @@
+// Package templates implements the templates eject command
+package templates
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/templates"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// ejectForce tells whether an existing override should be replaced
+var ejectForce bool
+
+// EjectCmd represents the templates eject command
+// It copies built-in templates to the templates directory for customization
+var EjectCmd = &cobra.Command{
+	Use:   "eject [name...]",
+	Short: "Copy built-in templates to the templates directory",
+	Long: `Copy built-in templates to the templates directory, every template if no name is given.
+The copies take precedence over the built-in templates the next time 'tulip init' runs.`,
+	Args:      cobra.OnlyValidArgs,
+	ValidArgs: templates.Names(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names := args
+		if len(names) == 0 {
+			names = templates.Names()
+		}
+
+		paths := make([]string, 0, len(names))
+		for _, name := range names {
+			path, err := templates.Eject(name, ejectForce)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, path)
+			util.PrintSuccess("Ejected " + name + " to " + path)
+		}
+		util.AddResult("paths", paths)
+		util.PrintInfo("Run 'tulip init --yes' to generate the files from the ejected templates")
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(EjectCmd)
+	EjectCmd.Flags().BoolVarP(&ejectForce, "force", "f", false, "Replace templates that were already ejected")
+}
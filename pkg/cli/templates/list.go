@@ -0,0 +1,33 @@
+// Package templates implements the templates list command
+package templates
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/templates"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// ListCmd represents the templates list command
+// It prints every template along with the source it's read from
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the templates",
+	Long:  `List the templates of the generated files, telling which ones are overridden in the templates directory.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		list := templates.List()
+		for _, template := range list {
+			if template.Source == templates.SourceOverride {
+				util.PrintOutput(template.Name + " (" + template.Source + ": " + template.Path + ")")
+				continue
+			}
+			util.PrintOutput(template.Name + " (" + template.Source + ")")
+		}
+		util.AddResult("templates", list)
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(ListCmd)
+}
@@ -0,0 +1,44 @@
+// Package templates implements the templates show command
+package templates
+
+import (
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/templates"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// showBuiltIn tells whether the built-in template should be shown instead of its override
+var showBuiltIn bool
+
+// ShowCmd represents the templates show command
+// It prints the content of a template
+var ShowCmd = &cobra.Command{
+	Use:       "show <name>",
+	Short:     "Print a template",
+	Long:      `Print the template used to generate a file, from its override if there is one.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: templates.Names(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		read := templates.Read
+		if showBuiltIn {
+			read = templates.ReadBuiltIn
+		}
+		content, err := read(args[0])
+		if err != nil {
+			return err
+		}
+
+		for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+			util.PrintOutput(line)
+		}
+		util.AddResult("content", content)
+		return nil
+	},
+}
+
+func init() {
+	Cmd.AddCommand(ShowCmd)
+	ShowCmd.Flags().BoolVar(&showBuiltIn, "built-in", false, "Print the built-in template even if it's overridden")
+}
@@ -0,0 +1,14 @@
+// Package templates implements the commands for inspecting and customizing the templates of generated files
+package templates
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the base templates command
+var Cmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Inspect and customize the templates of generated files",
+	Long: `Commands for listing the templates Tulip generates its files from, and for ejecting them into
+the templates directory, where they take precedence over the built-in ones.`,
+}
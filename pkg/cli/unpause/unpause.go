@@ -0,0 +1,30 @@
+// Package unpause implements the 'unpause' command functionality
+package unpause
+
+import (
+	"github.com/pierrestoffe/tulip/pkg/cli/flags"
+	"github.com/pierrestoffe/tulip/pkg/project"
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the unpause command
+var Cmd = &cobra.Command{
+	Use:   "unpause",
+	Short: "Resume the project",
+	Long:  `Resume the paused containers of the project found in the current directory or any of its parents.`,
+	Args:  flags.NoProjectArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Find the targeted project
+		p, err := flags.ResolveProject(cmd)
+		if err != nil {
+			return err
+		}
+
+		_, err = project.Unpause(p)
+		return err
+	},
+}
+
+func init() {
+	flags.AddProject(Cmd)
+}
@@ -4,11 +4,9 @@ package config
 
 import (
 	"os"
-	"path/filepath"
 	"sync"
 
 	"github.com/pierrestoffe/tulip/pkg/util"
-	"gopkg.in/yaml.v3"
 )
 
 // Application constants define paths, versions, and file names used throughout the application
@@ -16,25 +14,48 @@ const (
 	// App-level constants
 	AppName    = "Tulip"  // Name of the application
 	AppVersion = "1.0.0"  // Current version of the application
-	AppRootDir = ".tulip" // Root directory for all Tulip configuration files
+	AppRootDir = ".tulip" // Default directory for all Tulip files in the home directory, see ResolveDirs
 
 	// Configuration-related constants
-	ConfigFile          = "config.yml" // Main configuration file name
-	ConfigVersion       = "1.0"        // Configuration file version
-	ConfigCertsDir      = "certs"      // Directory for SSL certificates
-	ConfigContainersDir = "containers" // Directory for container configurations
+	ConfigFile          = "config.yml"    // Main configuration file name
+	ConfigVersion       = "1.1"           // Configuration file schema version, see migrations
+	ConfigCertsDir      = "certs"         // Directory for SSL certificates, mounted in the proxy container
+	ConfigCADir         = "ca"            // Directory holding the root certificate authority, never mounted
+	ConfigContainersDir = "containers"    // Directory for container configurations
+	ConfigGeneratedDir  = ".generated"    // Directory recording the files generated by Tulip
+	ConfigChecksumsFile = "checksums.yml" // Checksums of the generated files, by path relative to Tulip's directory
+	ConfigTemplatesDir  = "templates"     // Directory holding the templates overriding the built-in ones
 
 	// Proxy-related constants
 	ProxyContainerName     = "tulip-proxy"        // Name of the proxy container
 	ProxyConfigDir         = "proxy"              // Directory for proxy configuration
 	ProxyDockerComposeFile = "docker-compose.yml" // Docker Compose file for proxy
 	ProxyTraefikFile       = "traefik.yml"        // Traefik configuration file
+	ProxyHTTPEntrypoint    = "80"                 // Port Traefik listens on for HTTP inside the proxy container
+	ProxyHTTPSEntrypoint   = "443"                // Port Traefik listens on for HTTPS inside the proxy container
 
 	// SSH-related constants
 	SSHContainerName     = "tulip-ssh"          // Name of the SSH container
 	SSHConfigDir         = "ssh"                // Directory for SSH configuration
 	SSHDockerComposeFile = "docker-compose.yml" // Docker Compose file for SSH
 	SSHDockerFile        = "Dockerfile"         // Dockerfile for SSH service
+
+	// Certificate-related constants
+	CertsCAFile     = "rootCA.pem"          // Root certificate of the local certificate authority
+	CertsCAKeyFile  = "rootCA-key.pem"      // Private key of the local certificate authority
+	CertsCertFile   = "cert.pem"            // Certificate issued for a project
+	CertsKeyFile    = "key.pem"             // Private key of the certificate issued for a project
+	CertsMountPath  = "/etc/traefik/certs/" // Path where the certs directory is mounted in the proxy container
+	CertsTLSFileExt = ".yml"                // Extension of the Traefik dynamic configuration files
+
+	// Project-related constants
+	ProjectManifestFile      = ".tulip.yml"         // Manifest file found at the root of a project
+	ProjectDockerComposeFile = "docker-compose.yml" // Generated Docker Compose file for a project
+	ProjectStateFile         = "project.yml"        // Records where a project lives on disk
+
+	// DNS-related constants
+	DNSPidFile = "dns.pid" // File holding the process ID of the background DNS resolver
+	DNSLogFile = "dns.log" // File receiving the output of the background DNS resolver
 )
 
 // Config represents the application configuration
@@ -42,10 +63,13 @@ type Config struct {
 	Docker DockerConfig `yaml:"docker"`
 	Proxy  ProxyConfig  `yaml:"proxy"`
 	SSH    SSHConfig    `yaml:"ssh"`
+	DNS    DNSConfig    `yaml:"dns"`
+	Hosts  HostsConfig  `yaml:"hosts"`
 }
 
 // DockerConfig holds Docker-related configuration
 type DockerConfig struct {
+	Runtime     string `yaml:"runtime"`
 	Sock        string `yaml:"sock"`
 	ProjectName string `yaml:"projectName"`
 	NetworkName string `yaml:"networkName"`
@@ -65,6 +89,19 @@ type SSHConfig struct {
 	Port      string `yaml:"port"`
 }
 
+// DNSConfig holds configuration of the built-in DNS resolver
+type DNSConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	TLD      string `yaml:"tld"`
+	Port     string `yaml:"port"`
+	Upstream string `yaml:"upstream"`
+}
+
+// HostsConfig holds configuration of the managed hosts file entries
+type HostsConfig struct {
+	File string `yaml:"file"`
+}
+
 var (
 	// Global configuration instance
 	config      *Config
@@ -75,7 +112,8 @@ var (
 func DefaultConfig() *Config {
 	return &Config{
 		Docker: DockerConfig{
-			Sock:        "/var/run/docker.sock",
+			Runtime:     RuntimeDocker,
+			Sock:        "",
 			ProjectName: "tulip",
 			NetworkName: "tulip",
 		},
@@ -89,6 +127,15 @@ func DefaultConfig() *Config {
 			ImageName: "ssh",
 			Port:      "8851",
 		},
+		DNS: DNSConfig{
+			Enabled:  true,
+			TLD:      "tulip.test",
+			Port:     "10053",
+			Upstream: "",
+		},
+		Hosts: HostsConfig{
+			File: "/etc/hosts",
+		},
 	}
 }
 
@@ -103,43 +150,66 @@ func Load(initialize bool) (*Config, error) {
 		return config, nil // Return cached config if already loaded
 	}
 
-	// Set default configuration
-	config = DefaultConfig()
-
-	// Check if config file exists
-	tulipDirPath := GetTulipDirPath()
-	if _, err := os.Stat(tulipDirPath); !os.IsNotExist(err) {
-		tulipDir, err := GetTulipDir()
+	// Read the configuration file, if any
+	configPath := GetConfigFilePath()
+	if _, err := os.Stat(configPath); err == nil {
+		configFileData, err = os.ReadFile(configPath)
 		if err != nil {
-			return nil, err
-		}
-		configPath := filepath.Join(tulipDir, ConfigFile)
-
-		// Check if config file exists
-		if _, err := os.Stat(configPath); os.IsNotExist(err) {
-			return config, nil
+			return nil, util.NewError(util.ErrConfigInvalid, "Failed to read configuration file", err)
 		}
 
-		// Read config file
-		configFileData, err = os.ReadFile(configPath)
+		// Upgrade files written for older schema versions
+		configFileData, err = migrateFile(configPath, configFileData)
 		if err != nil {
-			return nil, util.HandleError("Failed to read configuration file", err)
+			return nil, err
 		}
+	} else if configFilePath != "" && !initialize {
+		// A file selected with --config must exist, unless it's about to be created
+		return nil, util.NewError(util.ErrUsage, "Configuration file not found: "+configPath, err)
 	}
 
-	// Parse YAML
-	if len(configFileData) > 0 {
-		if err := yaml.Unmarshal(configFileData, config); err != nil {
-			return nil, util.HandleError("Failed to parse configuration file", err)
-		}
+	// Apply the environment and flags on top of the file, and make sure that the values are valid
+	parsed, valueOrigins, err := resolve(configPath, configFileData)
+	if err != nil {
+		config = nil
+		return nil, err
 	}
+	config = parsed
+	origins = valueOrigins
+
+	return config, nil
+}
 
-	// Make sure that key configuration values are valid
-	if err := validateConfig(config); err != nil {
-		return nil, util.HandleError("Invalid configuration", err)
+// LoadFile reads the configuration file on top of the defaults, leaving out the environment and flags
+// Returns the defaults if the file doesn't exist yet
+func LoadFile() (*Config, error) {
+	configPath := GetConfigFilePath()
+	content, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return DefaultConfig(), nil
+	} else if err != nil {
+		return nil, util.NewError(util.ErrConfigInvalid, "Failed to read configuration file", err)
 	}
 
-	return config, nil
+	// Upgrade files written for older schema versions
+	content, err = migrateFile(configPath, content)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(content)
+}
+
+// Parse reads the content of a configuration file on top of the default configuration
+// Returns an error if the content isn't valid YAML or holds invalid values, listing all of them
+func Parse(content []byte) (*Config, error) {
+	cfg, problems, err := Check(content)
+	if err != nil {
+		return nil, err
+	}
+	if err := problemsError(problems); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
 // Get returns the current configuration
@@ -154,28 +224,15 @@ func Get() (*Config, error) {
 	return Load(false)
 }
 
+// Set replaces the current configuration, e.g. with defaults when reinitializing
+func Set(cfg *Config) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	config = cfg
+	origins = nil
+}
+
 // Initialize initializes configuration
 func Initialize() (*Config, error) {
 	return Load(true)
 }
-
-// validateConfig ensures the configuration has valid values
-func validateConfig(cfg *Config) error {
-	if cfg.Docker.ProjectName == "" {
-		return util.HandleError("Docker project name cannot be empty", nil)
-	}
-	if cfg.Docker.NetworkName == "" {
-		return util.HandleError("Docker network name cannot be empty", nil)
-	}
-	if cfg.Docker.Sock == "" {
-		return util.HandleError("Docker socket path cannot be empty", nil)
-	}
-	if cfg.Proxy.ImageName == "" {
-		return util.HandleError("Proxy image name cannot be empty", nil)
-	}
-	if cfg.SSH.ImageName == "" {
-		return util.HandleError("SSH image name cannot be empty", nil)
-	}
-
-	return nil
-}
@@ -3,6 +3,7 @@
 package config
 
 import (
+	"cmp"
 	"os"
 	"path/filepath"
 
@@ -19,29 +20,131 @@ func GetUserHomeDir() (string, error) {
 	return homeDir, nil
 }
 
-// GetTulipDirPath constructs the full path to Tulip's configuration directory
-func GetTulipDirPath() string {
+// Environment variables selecting where Tulip keeps its files
+const (
+	EnvHome       = "TULIP_HOME"      // Single directory holding every file, like ~/.tulip
+	EnvXDGConfig  = "XDG_CONFIG_HOME" // Base directory of configuration files
+	EnvXDGState   = "XDG_STATE_HOME"  // Base directory of state files
+	EnvXDGCache   = "XDG_CACHE_HOME"  // Base directory of cache files
+	xdgDirName    = "tulip"           // Name of Tulip's directory in the XDG base directories
+	xdgConfigBase = ".config"         // Default XDG configuration directory, relative to the home directory
+	xdgStateBase  = ".local/state"    // Default XDG state directory, relative to the home directory
+	xdgCacheBase  = ".cache"          // Default XDG cache directory, relative to the home directory
+)
+
+// Dirs holds the directories where Tulip keeps its files
+type Dirs struct {
+	Config string // Configuration file, certificates and container configurations
+	State  string // Files describing running processes, such as the DNS resolver's PID and log
+	Cache  string // Files that can be recreated at any time
+}
+
+// ResolveDirs determines the directories where Tulip keeps its files
+// $TULIP_HOME holds every file when it's set. Otherwise the XDG base directories are used when one of them
+// is set and ~/.tulip doesn't exist yet, and ~/.tulip holds every file by default
+// Returns an error if the directories can't be determined, e.g. when the home directory is unknown
+func ResolveDirs() (Dirs, error) {
+	if home := os.Getenv(EnvHome); home != "" {
+		if !filepath.IsAbs(home) {
+			return Dirs{}, util.NewError(util.ErrUsage, EnvHome+" must be an absolute path: "+home, nil)
+		}
+		return Dirs{Config: home, State: home, Cache: home}, nil
+	}
+
 	homeDir, err := GetUserHomeDir()
 	if err != nil {
-		return ""
+		return Dirs{}, util.NewError(util.ErrNotInitialized, "Failed to locate Tulip's directory", err,
+			"Set "+EnvHome+" to the directory where Tulip should keep its files")
+	}
+	legacyDir := filepath.Join(homeDir, AppRootDir)
+
+	// Keep using ~/.tulip for existing installs and when XDG isn't configured
+	_, statErr := os.Stat(legacyDir)
+	xdgConfig, xdgState, xdgCache := xdgBaseDir(EnvXDGConfig), xdgBaseDir(EnvXDGState), xdgBaseDir(EnvXDGCache)
+	if statErr == nil || (xdgConfig == "" && xdgState == "" && xdgCache == "") {
+		return Dirs{Config: legacyDir, State: legacyDir, Cache: legacyDir}, nil
+	}
+
+	return Dirs{
+		Config: filepath.Join(cmp.Or(xdgConfig, filepath.Join(homeDir, xdgConfigBase)), xdgDirName),
+		State:  filepath.Join(cmp.Or(xdgState, filepath.Join(homeDir, xdgStateBase)), xdgDirName),
+		Cache:  filepath.Join(cmp.Or(xdgCache, filepath.Join(homeDir, xdgCacheBase)), xdgDirName),
+	}, nil
+}
+
+// xdgBaseDir returns the XDG base directory set in an environment variable
+// Relative paths are ignored, as required by the XDG Base Directory Specification
+func xdgBaseDir(name string) string {
+	if dir := os.Getenv(name); filepath.IsAbs(dir) {
+		return dir
+	}
+	return ""
+}
+
+// mustResolveDirs determines Tulip's directories for the path getters, which can't report errors
+// The CLI reports unresolvable directories through ResolveDirs before running any command, so an error here
+// is a bug and panics rather than letting the caller use paths relative to the working directory
+func mustResolveDirs() Dirs {
+	dirs, err := ResolveDirs()
+	if err != nil {
+		panic(err)
 	}
+	return dirs
+}
+
+// GetTulipDirPath constructs the full path to Tulip's configuration directory
+// Panics if the directory can't be determined, callers must have checked ResolveDirs first
+func GetTulipDirPath() string {
+	return mustResolveDirs().Config
+}
+
+// GetStateDirPath constructs the full path to Tulip's state directory
+// Panics if the directory can't be determined, callers must have checked ResolveDirs first
+func GetStateDirPath() string {
+	return mustResolveDirs().State
+}
 
-	return filepath.Join(homeDir, AppRootDir)
+// GetCacheDirPath constructs the full path to Tulip's cache directory
+// Panics if the directory can't be determined, callers must have checked ResolveDirs first
+func GetCacheDirPath() string {
+	return mustResolveDirs().Cache
 }
 
 // GetTulipDir verifies and returns the path to Tulip's configuration directory
 // Returns an error if the directory doesn't exist
 func GetTulipDir() (string, error) {
-	tulipDirPath := GetTulipDirPath()
+	dirs, err := ResolveDirs()
+	if err != nil {
+		return "", err
+	}
+	tulipDirPath := dirs.Config
 
 	// Check if directory exists
 	if _, err := os.Stat(tulipDirPath); os.IsNotExist(err) {
-		return "", util.HandleError("Tulip home directory does not exist", err)
+		return "", util.NewError(util.ErrNotInitialized, "Tulip home directory does not exist", err)
 	}
 
 	return tulipDirPath, nil
 }
 
+// GetConfigFilePath constructs the full path to the configuration file, or returns the file selected with --config
+func GetConfigFilePath() string {
+	if configFilePath != "" {
+		return configFilePath
+	}
+	return filepath.Join(GetTulipDirPath(), ConfigFile)
+}
+
+// GetGeneratedDirPath constructs the full path to the directory recording the files generated by Tulip
+func GetGeneratedDirPath() string {
+	return filepath.Join(GetTulipDirPath(), ConfigGeneratedDir)
+}
+
+// GetTemplatesDirPath constructs the full path to the directory holding the templates overriding the built-in ones
+func GetTemplatesDirPath() string {
+	return filepath.Join(GetTulipDirPath(), ConfigTemplatesDir)
+}
+
 // GetCertsConfigDirPath constructs the full path to the certificates directory
 func GetCertsConfigDirPath() string {
 	return filepath.Join(GetTulipDirPath(), ConfigCertsDir)
@@ -54,12 +157,23 @@ func GetCertsConfigDir() (string, error) {
 
 	// Check if directory exists
 	if _, err := os.Stat(certsConfigDirPath); os.IsNotExist(err) {
-		return "", util.HandleError("Certs config directory does not exist", err)
+		return "", util.NewError(util.ErrNotInitialized, "Certs config directory does not exist", err)
 	}
 
 	return certsConfigDirPath, nil
 }
 
+// GetCAConfigDirPath constructs the full path to the certificate authority directory
+// It lies outside of the certs directory so that the proxy container never sees the private key
+func GetCAConfigDirPath() string {
+	return filepath.Join(GetTulipDirPath(), ConfigCADir)
+}
+
+// GetProjectCertsDirPath constructs the full path to the directory holding the certificate of a project
+func GetProjectCertsDirPath(projectName string) string {
+	return filepath.Join(GetCertsConfigDirPath(), projectName)
+}
+
 // GetContainersConfigDirPath constructs the full path to the containers configuration directory
 func GetContainersConfigDirPath() string {
 	return filepath.Join(GetTulipDirPath(), ConfigContainersDir)
@@ -72,7 +186,7 @@ func GetContainersConfigDir() (string, error) {
 
 	// Check if directory exists
 	if _, err := os.Stat(containersConfigDirPath); os.IsNotExist(err) {
-		return "", util.HandleError("Containers config directory does not exist", err)
+		return "", util.NewError(util.ErrNotInitialized, "Containers config directory does not exist", err)
 	}
 
 	return containersConfigDirPath, nil
@@ -90,7 +204,7 @@ func GetProxyConfigDir() (string, error) {
 
 	// Check if directory exists
 	if _, err := os.Stat(proxyConfigDirPath); os.IsNotExist(err) {
-		return "", util.HandleError("Proxy config directory does not exist", err)
+		return "", util.NewError(util.ErrNotInitialized, "Proxy config directory does not exist", err)
 	}
 
 	return proxyConfigDirPath, nil
@@ -108,8 +222,13 @@ func GetSSHConfigDir() (string, error) {
 
 	// Check if directory exists
 	if _, err := os.Stat(sshConfigDirPath); os.IsNotExist(err) {
-		return "", util.HandleError("SSH config directory does not exist", err)
+		return "", util.NewError(util.ErrNotInitialized, "SSH config directory does not exist", err)
 	}
 
 	return sshConfigDirPath, nil
 }
+
+// GetProjectConfigDirPath constructs the full path to the configuration directory of a project
+func GetProjectConfigDirPath(projectName string) string {
+	return filepath.Join(GetContainersConfigDirPath(), projectName)
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+func TestResolveDirs(t *testing.T) {
+	home := t.TempDir()
+	legacyHome := t.TempDir()
+	if err := os.Mkdir(filepath.Join(legacyHome, AppRootDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		home string
+		env  map[string]string
+		want Dirs
+	}{
+		{
+			name: "defaults to the legacy directory",
+			home: home,
+			want: Dirs{Config: filepath.Join(home, AppRootDir), State: filepath.Join(home, AppRootDir), Cache: filepath.Join(home, AppRootDir)},
+		},
+		{
+			name: "TULIP_HOME holds every file",
+			home: legacyHome,
+			env:  map[string]string{EnvHome: "/srv/tulip", EnvXDGConfig: "/xdg/config"},
+			want: Dirs{Config: "/srv/tulip", State: "/srv/tulip", Cache: "/srv/tulip"},
+		},
+		{
+			name: "XDG directories when one of them is set",
+			home: home,
+			env:  map[string]string{EnvXDGConfig: "/xdg/config"},
+			want: Dirs{Config: "/xdg/config/tulip", State: filepath.Join(home, xdgStateBase, xdgDirName), Cache: filepath.Join(home, xdgCacheBase, xdgDirName)},
+		},
+		{
+			name: "XDG cache directory alone",
+			home: home,
+			env:  map[string]string{EnvXDGCache: "/xdg/cache"},
+			want: Dirs{Config: filepath.Join(home, xdgConfigBase, xdgDirName), State: filepath.Join(home, xdgStateBase, xdgDirName), Cache: "/xdg/cache/tulip"},
+		},
+		{
+			name: "relative XDG directories are ignored",
+			home: home,
+			env:  map[string]string{EnvXDGConfig: "config", EnvXDGState: "/xdg/state"},
+			want: Dirs{Config: filepath.Join(home, xdgConfigBase, xdgDirName), State: "/xdg/state/tulip", Cache: filepath.Join(home, xdgCacheBase, xdgDirName)},
+		},
+		{
+			name: "existing legacy directory wins over XDG",
+			home: legacyHome,
+			env:  map[string]string{EnvXDGConfig: "/xdg/config", EnvXDGState: "/xdg/state", EnvXDGCache: "/xdg/cache"},
+			want: Dirs{Config: filepath.Join(legacyHome, AppRootDir), State: filepath.Join(legacyHome, AppRootDir), Cache: filepath.Join(legacyHome, AppRootDir)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("HOME", test.home)
+			for _, name := range []string{EnvHome, EnvXDGConfig, EnvXDGState, EnvXDGCache} {
+				t.Setenv(name, test.env[name])
+			}
+
+			got, err := ResolveDirs()
+			if err != nil {
+				t.Fatalf("ResolveDirs returned %v", err)
+			}
+			if got != test.want {
+				t.Errorf("ResolveDirs = %+v, want %+v", got, test.want)
+			}
+			if path := GetTulipDirPath(); path != test.want.Config {
+				t.Errorf("GetTulipDirPath = %s, want %s", path, test.want.Config)
+			}
+			if path := GetStateDirPath(); path != test.want.State {
+				t.Errorf("GetStateDirPath = %s, want %s", path, test.want.State)
+			}
+			if path := GetCacheDirPath(); path != test.want.Cache {
+				t.Errorf("GetCacheDirPath = %s, want %s", path, test.want.Cache)
+			}
+		})
+	}
+}
+
+func TestResolveDirsRelativeHome(t *testing.T) {
+	t.Setenv(EnvHome, "tulip")
+	if _, err := ResolveDirs(); !errors.Is(err, util.ErrUsage) {
+		t.Errorf("ResolveDirs returned %v, want ErrUsage", err)
+	}
+
+	// Getters must not fall back to paths relative to the working directory
+	for name, get := range map[string]func() string{
+		"GetTulipDirPath":   GetTulipDirPath,
+		"GetStateDirPath":   GetStateDirPath,
+		"GetCacheDirPath":   GetCacheDirPath,
+		"GetConfigFilePath": GetConfigFilePath,
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s didn't panic", name)
+				}
+			}()
+			t.Errorf("%s = %q", name, get())
+		}()
+	}
+}
+
+func TestGetTulipDir(t *testing.T) {
+	home := filepath.Join(t.TempDir(), "tulip")
+	t.Setenv(EnvHome, home)
+	if _, err := GetTulipDir(); !errors.Is(err, util.ErrNotInitialized) {
+		t.Errorf("GetTulipDir returned %v before the directory exists, want ErrNotInitialized", err)
+	}
+
+	if err := os.Mkdir(home, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if dir, err := GetTulipDir(); err != nil || dir != home {
+		t.Errorf("GetTulipDir = %s, %v, want %s", dir, err, home)
+	}
+	if path := GetConfigFilePath(); path != filepath.Join(home, ConfigFile) {
+		t.Errorf("GetConfigFilePath = %s", path)
+	}
+}
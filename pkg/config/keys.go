@@ -0,0 +1,151 @@
+// Package config provides access to configuration values through dotted keys such as proxy.httpPort
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Keys returns the dotted keys of every configuration value, in file order
+func Keys() []string {
+	keys := make([]string, 0)
+	for _, field := range fields(DefaultConfig()) {
+		keys = append(keys, field.key)
+	}
+	return keys
+}
+
+// GetValue returns a configuration value as a string
+func GetValue(cfg *Config, key string) (string, error) {
+	value, err := lookupField(cfg, key)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(value.Interface()), nil
+}
+
+// SetValue changes a value in the content of a configuration file
+// Comments and the order of the keys are kept, and the result is validated as a whole
+// Returns the changed content
+func SetValue(content []byte, key string, value string) ([]byte, error) {
+	field, err := lookupField(DefaultConfig(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the value against the type of the key
+	valueNode := &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	switch field.Kind() {
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, util.NewError(util.ErrUsage, "Invalid value for "+key+": "+value, nil, "Expected true or false")
+		}
+		valueNode.Tag = "!!bool"
+		valueNode.Value = strconv.FormatBool(parsed)
+	default:
+		// Numbers are written unquoted like in generated files, other strings are quoted when needed
+		if _, err := strconv.Atoi(value); err != nil {
+			valueNode.Tag = "!!str"
+		}
+		if isPortKey(key) {
+			if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+				return nil, util.NewError(util.ErrUsage, "Invalid value for "+key+": "+value, nil, "Expected a port number between 1 and 65535")
+			}
+		}
+	}
+
+	// Change the value in the document
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, util.NewError(util.ErrConfigInvalid, "Failed to parse configuration file", err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	section, name, _ := strings.Cut(key, ".")
+	setMappingValue(ensureMapping(doc.Content[0], section), name, valueNode)
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, util.HandleError("Failed to encode configuration", err)
+	}
+
+	// Make sure the whole configuration is still valid
+	if _, err := Parse(buffer.Bytes()); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// setField changes a configuration value from its string representation
+func setField(cfg *Config, key string, value string) error {
+	field, err := lookupField(cfg, key)
+	if err != nil {
+		return err
+	}
+	switch field.Kind() {
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", value)
+		}
+		field.SetBool(parsed)
+	default:
+		field.SetString(value)
+	}
+	return nil
+}
+
+// field is a configuration value along with its dotted key
+type field struct {
+	key   string
+	value reflect.Value
+}
+
+// fields lists the values of a configuration, section by section
+func fields(cfg *Config) []field {
+	fields := make([]field, 0)
+	root := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Field(i)
+		sectionKey := yamlKey(root.Type().Field(i))
+		for j := 0; j < section.NumField(); j++ {
+			fields = append(fields, field{
+				key:   sectionKey + "." + yamlKey(section.Type().Field(j)),
+				value: section.Field(j),
+			})
+		}
+	}
+	return fields
+}
+
+// lookupField returns the value of a configuration matching a dotted key
+func lookupField(cfg *Config, key string) (reflect.Value, error) {
+	for _, field := range fields(cfg) {
+		if field.key == key {
+			return field.value, nil
+		}
+	}
+	return reflect.Value{}, util.NewError(util.ErrUsage, "Unknown configuration key: "+key, nil,
+		"Run 'tulip config list' to see the available keys")
+}
+
+// yamlKey returns the name of a struct field in the configuration file
+func yamlKey(structField reflect.StructField) string {
+	name, _, _ := strings.Cut(structField.Tag.Get("yaml"), ",")
+	return name
+}
+
+// isPortKey checks if a dotted key holds a port number
+func isPortKey(key string) bool {
+	return strings.HasSuffix(strings.ToLower(key), "port")
+}
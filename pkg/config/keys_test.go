@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// Configuration file with comments around the values changed by the tests
+const commentedConfig = "# Tulip configuration\n" +
+	"version: 1.1\n" +
+	"proxy:\n" +
+	"    # Published ports\n" +
+	"    httpPort: 80 # default\n" +
+	"    httpsPort: 443\n"
+
+func TestSetValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+		value   string
+		want    string
+		err     error
+	}{
+		{
+			name:    "changes value and keeps comments",
+			content: commentedConfig,
+			key:     "proxy.httpPort",
+			value:   "8080",
+			want: "# Tulip configuration\n" +
+				"version: 1.1\n" +
+				"proxy:\n" +
+				"    # Published ports\n" +
+				"    httpPort: 8080 # default\n" +
+				"    httpsPort: 443\n",
+		},
+		{
+			name:    "adds missing section",
+			content: commentedConfig,
+			key:     "dns.enabled",
+			value:   "false",
+			want:    commentedConfig + "dns:\n    enabled: false\n",
+		},
+		{
+			name:    "quotes strings that would read as another type",
+			content: commentedConfig,
+			key:     "docker.networkName",
+			value:   "true",
+			want:    commentedConfig + "docker:\n    networkName: \"true\"\n",
+		},
+		{
+			name:  "writes to empty file",
+			key:   "dns.port",
+			value: "1053",
+			want:  "dns:\n    port: 1053\n",
+		},
+		{
+			name:    "rejects unknown key",
+			content: commentedConfig,
+			key:     "proxy.bogus",
+			value:   "1",
+			err:     util.ErrUsage,
+		},
+		{
+			name:    "rejects invalid boolean",
+			content: commentedConfig,
+			key:     "dns.enabled",
+			value:   "no",
+			err:     util.ErrUsage,
+		},
+		{
+			name:    "rejects invalid port",
+			content: commentedConfig,
+			key:     "proxy.httpPort",
+			value:   "70000",
+			err:     util.ErrUsage,
+		},
+		{
+			name:    "rejects value making the configuration invalid",
+			content: commentedConfig,
+			key:     "docker.runtime",
+			value:   "bogus",
+			err:     util.ErrConfigInvalid,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := SetValue([]byte(test.content), test.key, test.value)
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("SetValue returned %v, want %v", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetValue returned %v", err)
+			}
+			if string(got) != test.want {
+				t.Errorf("SetValue =\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetValue(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Proxy.HTTPSPort = "8443"
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"proxy.httpsPort", "8443"},
+		{"dns.enabled", "true"},
+		{"hosts.file", "/etc/hosts"},
+	}
+	for _, test := range tests {
+		if got, err := GetValue(cfg, test.key); err != nil || got != test.want {
+			t.Errorf("GetValue(%s) = %q, %v, want %q", test.key, got, err, test.want)
+		}
+	}
+	if _, err := GetValue(cfg, "proxy"); !errors.Is(err, util.ErrUsage) {
+		t.Errorf("GetValue(proxy) returned %v, want ErrUsage", err)
+	}
+}
+
+func TestSetValueRoundTrip(t *testing.T) {
+	for _, key := range Keys() {
+		value, err := GetValue(DefaultConfig(), key)
+		if err != nil {
+			t.Fatalf("GetValue(%s) returned %v", key, err)
+		}
+		if value == "" {
+			continue
+		}
+		content, err := SetValue([]byte(commentedConfig), key, value)
+		if err != nil {
+			t.Errorf("SetValue(%s, %q) returned %v", key, value, err)
+			continue
+		}
+		cfg, err := Parse(content)
+		if err != nil {
+			t.Fatalf("Parse returned %v", err)
+		}
+		if got, _ := GetValue(cfg, key); got != value {
+			t.Errorf("%s = %q after SetValue, want %q", key, got, value)
+		}
+	}
+}
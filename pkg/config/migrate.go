@@ -0,0 +1,182 @@
+// Package config provides the migrations upgrading configuration files written for older schema versions
+package config
+
+import (
+	"bytes"
+	"os"
+	"slices"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Schema version assumed for configuration files without a version
+const initialConfigVersion = "1.0"
+
+// Migration upgrades a configuration document from one schema version to the next
+type Migration struct {
+	From        string                     // Schema version the migration applies to
+	To          string                     // Schema version the migration produces
+	Description string                     // What the migration changes
+	Apply       func(doc *yaml.Node) error // Changes the root mapping of the document in place
+}
+
+// migrations lists the migrations in the order they're applied
+// The last one must produce ConfigVersion
+var migrations = []Migration{
+	{
+		From:        "1.0",
+		To:          "1.1",
+		Description: "Add the container runtime, DNS and hosts settings, and auto-detect the Docker socket",
+		Apply: func(doc *yaml.Node) error {
+			docker := ensureMapping(doc, "docker")
+			if sock := mappingValue(docker, "sock"); sock != nil && sock.Value == "/var/run/docker.sock" {
+				// The former default prevented detecting rootless and Podman sockets
+				sock.Value = ""
+				sock.Style = yaml.DoubleQuotedStyle
+			}
+			if mappingValue(docker, "runtime") == nil {
+				prependMappingValue(docker, "runtime", scalarNode(RuntimeDocker))
+			}
+
+			defaults := DefaultConfig()
+			if err := ensureSection(doc, "dns", defaults.DNS); err != nil {
+				return err
+			}
+			return ensureSection(doc, "hosts", defaults.Hosts)
+		},
+	},
+}
+
+// Migrate upgrades the content of a configuration file to the current schema version
+// Returns the upgraded content along with the migrations that were applied, none if it was up to date
+func Migrate(content []byte) ([]byte, []Migration, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, nil, util.NewError(util.ErrConfigInvalid, "Failed to parse configuration file", err)
+	}
+	if len(doc.Content) == 0 {
+		return content, nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, util.NewError(util.ErrConfigInvalid, "Configuration file must contain a mapping", nil)
+	}
+
+	// Find the migrations to apply
+	version := initialConfigVersion
+	if node := mappingValue(root, "version"); node != nil {
+		version = node.Value
+	}
+	if version == ConfigVersion {
+		return content, nil, nil
+	}
+	start := slices.IndexFunc(migrations, func(m Migration) bool { return m.From == version })
+	if start == -1 {
+		return nil, nil, util.NewError(util.ErrConfigInvalid, "Unsupported configuration version: "+version, nil,
+			"This version of "+AppName+" supports configuration version "+ConfigVersion)
+	}
+
+	applied := migrations[start:]
+	for _, migration := range applied {
+		if err := migration.Apply(root); err != nil {
+			return nil, nil, util.HandleError("Failed to migrate configuration from version "+migration.From+" to "+migration.To, err)
+		}
+	}
+	setMappingValue(root, "version", &yaml.Node{Kind: yaml.ScalarNode, Value: ConfigVersion})
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, nil, util.HandleError("Failed to encode migrated configuration", err)
+	}
+	return buffer.Bytes(), applied, nil
+}
+
+// migrateFile upgrades a configuration file in place, keeping a backup of the previous content
+// Returns the content of the file once upgraded
+func migrateFile(configPath string, content []byte) ([]byte, error) {
+	migrated, applied, err := Migrate(content)
+	if err != nil || len(applied) == 0 {
+		return migrated, err
+	}
+
+	// The backup is as private as the file
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return nil, util.HandleError("Failed to back up configuration file", err)
+	}
+	backupPath := GetConfigBackupFilePath(configPath, applied[0].From)
+	if err := util.WriteFileAtomic(backupPath, content, info.Mode().Perm()); err != nil {
+		return nil, util.HandleError("Failed to back up configuration file", err)
+	}
+	if err := util.UpdateFileAtomic(configPath, migrated); err != nil {
+		return nil, util.HandleError("Failed to write migrated configuration file", err)
+	}
+
+	util.PrintInfo("Migrated configuration from version " + applied[0].From + " to " + ConfigVersion)
+	util.PrintVerbose("Previous configuration saved to " + backupPath)
+	return migrated, nil
+}
+
+// GetConfigBackupFilePath returns the path where a configuration file is backed up before being migrated
+func GetConfigBackupFilePath(configPath string, version string) string {
+	return configPath + "." + version + ".bak"
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil if the key doesn't exist
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue replaces the value of a key in a mapping node, adding the key at the end if needed
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value.HeadComment = mapping.Content[i+1].HeadComment
+			value.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, scalarNode(key), value)
+}
+
+// prependMappingValue adds a key at the start of a mapping node
+func prependMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	mapping.Content = append([]*yaml.Node{scalarNode(key), value}, mapping.Content...)
+}
+
+// ensureMapping returns the mapping stored under a key, creating it if needed
+func ensureMapping(mapping *yaml.Node, key string) *yaml.Node {
+	if value := mappingValue(mapping, key); value != nil && value.Kind == yaml.MappingNode {
+		return value
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	setMappingValue(mapping, key, value)
+	return value
+}
+
+// ensureSection adds a section holding the given defaults if the mapping doesn't have it yet
+func ensureSection(mapping *yaml.Node, key string, defaults any) error {
+	if mappingValue(mapping, key) != nil {
+		return nil
+	}
+	var value yaml.Node
+	if err := value.Encode(defaults); err != nil {
+		return err
+	}
+	setMappingValue(mapping, key, &value)
+	return nil
+}
+
+// scalarNode creates a string node
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
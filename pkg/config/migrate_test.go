@@ -0,0 +1,157 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		applied int
+		err     error
+	}{
+		{
+			name: "upgrades 1.0 and keeps comments",
+			content: "# Tulip configuration\n" +
+				"docker:\n" +
+				"    # Socket\n" +
+				"    sock: /var/run/docker.sock # former default\n" +
+				"    networkName: tulip\n" +
+				"proxy:\n" +
+				"    httpPort: \"8080\"\n",
+			want: "# Tulip configuration\n" +
+				"docker:\n" +
+				"    runtime: docker\n" +
+				"    # Socket\n" +
+				"    sock: \"\" # former default\n" +
+				"    networkName: tulip\n" +
+				"proxy:\n" +
+				"    httpPort: \"8080\"\n" +
+				"dns:\n" +
+				"    enabled: true\n" +
+				"    tld: tulip.test\n" +
+				"    port: \"10053\"\n" +
+				"    upstream: \"\"\n" +
+				"hosts:\n" +
+				"    file: /etc/hosts\n" +
+				"version: 1.1\n",
+			applied: 1,
+		},
+		{
+			name: "keeps existing 1.0 settings",
+			content: "version: \"1.0\"\n" +
+				"docker:\n" +
+				"    runtime: podman\n" +
+				"    sock: /run/podman/podman.sock\n" +
+				"hosts:\n" +
+				"    file: /tmp/hosts\n",
+			want: "version: 1.1\n" +
+				"docker:\n" +
+				"    runtime: podman\n" +
+				"    sock: /run/podman/podman.sock\n" +
+				"hosts:\n" +
+				"    file: /tmp/hosts\n" +
+				"dns:\n" +
+				"    enabled: true\n" +
+				"    tld: tulip.test\n" +
+				"    port: \"10053\"\n" +
+				"    upstream: \"\"\n",
+			applied: 1,
+		},
+		{
+			name:    "leaves up-to-date file untouched",
+			content: "version: \"1.1\"\n# Comment   with spacing\ndocker:\n  sock: /var/run/docker.sock\n",
+			want:    "version: \"1.1\"\n# Comment   with spacing\ndocker:\n  sock: /var/run/docker.sock\n",
+		},
+		{
+			name:    "leaves empty file untouched",
+			content: "",
+			want:    "",
+		},
+		{
+			name:    "rejects unknown version",
+			content: "version: \"0.9\"\n",
+			err:     util.ErrConfigInvalid,
+		},
+		{
+			name:    "rejects newer version",
+			content: "version: \"2.0\"\n",
+			err:     util.ErrConfigInvalid,
+		},
+		{
+			name:    "rejects non-mapping document",
+			content: "- docker\n",
+			err:     util.ErrConfigInvalid,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, applied, err := Migrate([]byte(test.content))
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("Migrate returned %v, want %v", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Migrate returned %v", err)
+			}
+			if string(got) != test.want {
+				t.Errorf("Migrate =\n%s\nwant\n%s", got, test.want)
+			}
+			if len(applied) != test.applied {
+				t.Errorf("Migrate applied %d migrations, want %d", len(applied), test.applied)
+			}
+		})
+	}
+}
+
+func TestMigrationsEndAtConfigVersion(t *testing.T) {
+	if last := migrations[len(migrations)-1]; last.To != ConfigVersion {
+		t.Errorf("last migration produces version %s, want %s", last.To, ConfigVersion)
+	}
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].From != migrations[i-1].To {
+			t.Errorf("migration %d starts at %s, want %s", i, migrations[i].From, migrations[i-1].To)
+		}
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFile)
+	content := []byte("docker:\n    sock: /var/run/docker.sock\n")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, err := migrateFile(path, content)
+	if err != nil {
+		t.Fatalf("migrateFile returned %v", err)
+	}
+	if written, _ := os.ReadFile(path); string(written) != string(migrated) {
+		t.Errorf("configuration file holds %q, want %q", written, migrated)
+	}
+
+	backupPath := GetConfigBackupFilePath(path, "1.0")
+	if backupPath != path+".1.0.bak" {
+		t.Errorf("backup path = %s", backupPath)
+	}
+	if backup, err := os.ReadFile(backupPath); err != nil || string(backup) != string(content) {
+		t.Errorf("backup holds %q, %v, want %q", backup, err, content)
+	}
+
+	// Migrating again doesn't touch the file or its backup
+	if again, err := migrateFile(path, migrated); err != nil || string(again) != string(migrated) {
+		t.Errorf("second migrateFile = %q, %v", again, err)
+	}
+	if backup, _ := os.ReadFile(backupPath); string(backup) != string(content) {
+		t.Errorf("backup was overwritten with %q", backup)
+	}
+}
@@ -0,0 +1,161 @@
+// Package config provides the layers a configuration is built from, along with the origin of each value
+package config
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// Sources of a configuration value, from the lowest to the highest precedence
+const (
+	OriginDefault = "default" // Built-in default value
+	OriginFile    = "file"    // Configuration file
+	OriginEnv     = "env"     // Environment variable such as TULIP_PROXY_HTTPPORT
+	OriginFlag    = "flag"    // --set flag
+)
+
+// Prefix of the environment variables overriding configuration values
+const EnvPrefix = "TULIP_"
+
+// Origin tells where a configuration value comes from
+type Origin struct {
+	Source string // One of the Origin constants
+	Name   string // Path of the file, name of the environment variable or flag the value comes from
+	Line   int    // Line of the value in the configuration file
+}
+
+var (
+	// Path of the configuration file selected with --config, empty for the default one
+	configFilePath string
+	// Values set with --set, by dotted key
+	flagOverrides map[string]string
+	// Origin of each value of the current configuration, by dotted key
+	origins map[string]Origin
+)
+
+// String describes the origin, e.g. file:/home/user/.tulip/config.yml:12 or env:TULIP_PROXY_HTTPPORT
+func (o Origin) String() string {
+	switch {
+	case o.Source == OriginFile && o.Line > 0:
+		return o.Source + ":" + o.Name + ":" + strconv.Itoa(o.Line)
+	case o.Name != "":
+		return o.Source + ":" + o.Name
+	default:
+		return o.Source
+	}
+}
+
+// EnvName returns the environment variable overriding a configuration key, e.g. TULIP_PROXY_HTTPPORT for proxy.httpPort
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// SetFilePath selects an alternate configuration file, or the default one if the path is empty
+// The configuration is loaded again the next time it's needed
+func SetFilePath(path string) {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	configFilePath = path
+	config = nil
+}
+
+// SetOverrides sets the values taking precedence over the configuration file and the environment
+// The configuration is loaded again the next time it's needed
+// Returns an ErrUsage error if a key doesn't exist
+func SetOverrides(overrides map[string]string) error {
+	for key := range overrides {
+		if _, err := lookupField(DefaultConfig(), key); err != nil {
+			return err
+		}
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	flagOverrides = maps.Clone(overrides)
+	config = nil
+	return nil
+}
+
+// GetOrigin returns where a value of the current configuration comes from
+func GetOrigin(key string) Origin {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+	if origin, found := origins[key]; found {
+		return origin
+	}
+	return Origin{Source: OriginDefault}
+}
+
+// OverrideArgs returns the flags selecting the same configuration file and overrides, for running Tulip again
+func OverrideArgs() []string {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+	args := make([]string, 0)
+	if configFilePath != "" {
+		args = append(args, "--config", configFilePath)
+	}
+	for _, key := range slices.Sorted(maps.Keys(flagOverrides)) {
+		args = append(args, "--set", key+"="+flagOverrides[key])
+	}
+	return args
+}
+
+// resolve builds a configuration from the defaults, the content of the configuration file, the environment and the flags
+// Returns the configuration along with the origin of each value, or an ErrConfigInvalid error listing every invalid value
+func resolve(path string, content []byte) (*Config, map[string]Origin, error) {
+	cfg, lines, _, err := decode(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Values found in the file
+	valueOrigins := make(map[string]Origin)
+	for _, key := range Keys() {
+		if line, found := lines[key]; found {
+			valueOrigins[key] = Origin{Source: OriginFile, Name: path, Line: line}
+		}
+	}
+
+	// Values set in the environment
+	for _, key := range Keys() {
+		name := EnvName(key)
+		value, found := os.LookupEnv(name)
+		if !found {
+			continue
+		}
+		if err := setField(cfg, key, value); err != nil {
+			return nil, nil, util.NewError(util.ErrConfigInvalid, "Invalid value for "+key+" in "+name, err)
+		}
+		valueOrigins[key] = Origin{Source: OriginEnv, Name: name}
+	}
+
+	// Values set with flags
+	for _, key := range slices.Sorted(maps.Keys(flagOverrides)) {
+		if err := setField(cfg, key, flagOverrides[key]); err != nil {
+			return nil, nil, util.NewError(util.ErrUsage, "Invalid value for "+key+" in --set", err)
+		}
+		valueOrigins[key] = Origin{Source: OriginFlag, Name: "--set " + key}
+	}
+
+	// Point each problem to where the value comes from
+	problems := validate(cfg)
+	for i, problem := range problems {
+		origin, found := valueOrigins[problem.Key]
+		switch {
+		case !found:
+		case origin.Source == OriginFile:
+			problems[i].Line = origin.Line
+		default:
+			problems[i].Message += " (from " + origin.String() + ")"
+		}
+	}
+	if err := problemsError(problems); err != nil {
+		return nil, nil, err
+	}
+	return cfg, valueOrigins, nil
+}
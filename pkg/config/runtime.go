@@ -0,0 +1,128 @@
+// Package config provides functionality for detecting the container runtime
+// and socket used by the Tulip application
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Container runtimes supported by Tulip
+const (
+	RuntimeDocker  = "docker"  // Docker Engine or Docker Desktop
+	RuntimePodman  = "podman"  // Podman, rootful or rootless
+	RuntimeNerdctl = "nerdctl" // containerd through nerdctl
+)
+
+// Runtimes lists the supported container runtimes
+var Runtimes = []string{RuntimeDocker, RuntimePodman, RuntimeNerdctl}
+
+// Lowest port an unprivileged process may bind when the kernel doesn't say otherwise
+const defaultUnprivilegedPortStart = 1024
+
+// Ports bound instead of privileged ones when the runtime is rootless
+const (
+	RootlessHTTPPort  = "8080"
+	RootlessHTTPSPort = "8443"
+)
+
+// Socket returns the path to the runtime's API socket
+// When no socket is configured, the first existing candidate for the runtime is used
+func (d DockerConfig) Socket() string {
+	if d.Sock != "" {
+		return d.Sock
+	}
+
+	candidates := socketCandidates(d.Runtime)
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return candidates[0]
+}
+
+// IsRootless checks if the container runtime runs without root privileges
+func (d DockerConfig) IsRootless() bool {
+	switch d.Runtime {
+	case RuntimePodman, RuntimeNerdctl:
+		return os.Geteuid() != 0
+	default:
+		// Rootless Docker listens on a socket in the user's runtime directory
+		runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+		socket := d.Socket()
+		return (runtimeDir != "" && strings.HasPrefix(socket, runtimeDir+"/")) || strings.HasPrefix(socket, "/run/user/")
+	}
+}
+
+// PublishedPorts returns the HTTP and HTTPS ports the proxy binds on the host
+// Rootless runtimes can't bind privileged ports, which are replaced by unprivileged ones
+func (c *Config) PublishedPorts() (string, string) {
+	httpPort, httpsPort := c.Proxy.HTTPPort, c.Proxy.HTTPSPort
+	if !c.Docker.IsRootless() {
+		return httpPort, httpsPort
+	}
+
+	portStart := UnprivilegedPortStart()
+	if port, err := strconv.Atoi(httpPort); err == nil && port < portStart {
+		httpPort = RootlessHTTPPort
+	}
+	if port, err := strconv.Atoi(httpsPort); err == nil && port < portStart {
+		httpsPort = RootlessHTTPSPort
+	}
+	return httpPort, httpsPort
+}
+
+// UnprivilegedPortStart returns the lowest port a rootless runtime can bind
+func UnprivilegedPortStart() int {
+	content, err := os.ReadFile("/proc/sys/net/ipv4/ip_unprivileged_port_start")
+	if err != nil {
+		return defaultUnprivilegedPortStart
+	}
+	port, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return defaultUnprivilegedPortStart
+	}
+	return port
+}
+
+// socketCandidates returns the usual socket locations of a runtime, in order of preference
+func socketCandidates(runtime string) []string {
+	candidates := make([]string, 0)
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	homeDir, _ := os.UserHomeDir()
+
+	switch runtime {
+	case RuntimePodman:
+		candidates = appendHostSocket(candidates, os.Getenv("CONTAINER_HOST"))
+		if runtimeDir != "" && os.Geteuid() != 0 {
+			candidates = append(candidates, filepath.Join(runtimeDir, "podman", "podman.sock"))
+		}
+		candidates = append(candidates, "/run/podman/podman.sock")
+	case RuntimeNerdctl:
+		if runtimeDir != "" && os.Geteuid() != 0 {
+			candidates = append(candidates, filepath.Join(runtimeDir, "containerd-rootless", "api.sock"))
+		}
+		candidates = append(candidates, "/run/containerd/containerd.sock")
+	default:
+		candidates = appendHostSocket(candidates, os.Getenv("DOCKER_HOST"))
+		candidates = append(candidates, "/var/run/docker.sock")
+		if homeDir != "" {
+			candidates = append(candidates, filepath.Join(homeDir, ".docker", "run", "docker.sock"))
+		}
+		if runtimeDir != "" {
+			candidates = append(candidates, filepath.Join(runtimeDir, "docker.sock"))
+		}
+	}
+	return candidates
+}
+
+// appendHostSocket adds the socket of a unix:// host URL to the candidates
+func appendHostSocket(candidates []string, host string) []string {
+	if socket, found := strings.CutPrefix(host, "unix://"); found && socket != "" {
+		return append(candidates, socket)
+	}
+	return candidates
+}
@@ -0,0 +1,262 @@
+// Package config provides the validation of configuration values, reporting every problem at once
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Problem describes an invalid or suspicious configuration value
+type Problem struct {
+	Key     string `json:"key"`            // Dotted key of the value, e.g. proxy.httpPort
+	Line    int    `json:"line,omitempty"` // Line of the value in the configuration file, 0 if it isn't in the file
+	Message string `json:"message"`        // What's wrong with the value
+	Warning bool   `json:"warning"`        // The configuration can still be used
+}
+
+// ValidationError lists the problems making a configuration invalid
+type ValidationError struct {
+	Problems []Problem
+}
+
+// Port of multicast DNS, usually held by Avahi or Bonjour
+const mdnsPort = "5353"
+
+var (
+	// Compose project names, as accepted by docker compose
+	projectNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	// Network names, as accepted by the Docker Engine
+	networkNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+	// Image references: [registry[:port]/]path[:tag][@digest]
+	imageReferencePattern = regexp.MustCompile(`^` +
+		`(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?/)?` +
+		`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+		`(?::[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127})?` +
+		`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?$`)
+	// Domain labels
+	labelPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?$`)
+)
+
+// Validate checks that a configuration has valid values
+// Returns an ErrConfigInvalid error listing every invalid value
+func Validate(cfg *Config) error {
+	return problemsError(validate(cfg))
+}
+
+// Check parses the content of a configuration file and reports every problem found, with its line
+// Unknown keys are reported as warnings, and so are values that only fail on this machine
+// Returns an error if the content can't be parsed at all
+func Check(content []byte) (*Config, []Problem, error) {
+	cfg, lines, unknown, err := decode(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	problems := validate(cfg)
+	for i := range problems {
+		problems[i].Line = lines[problems[i].Key]
+	}
+	for _, key := range unknown {
+		problems = append(problems, Problem{Key: key, Line: lines[key], Message: "unknown key, it's ignored", Warning: true})
+	}
+
+	slices.SortStableFunc(problems, func(a Problem, b Problem) int {
+		return a.Line - b.Line
+	})
+	return cfg, problems, nil
+}
+
+// decode reads the content of a configuration file on top of the default configuration
+// Returns the configuration along with the line of each key found and the keys that aren't part of the configuration
+func decode(content []byte) (*Config, map[string]int, []string, error) {
+	cfg := DefaultConfig()
+	if len(content) == 0 {
+		return cfg, map[string]int{}, nil, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, nil, nil, util.NewError(util.ErrConfigInvalid, "Failed to parse configuration file", err)
+	}
+	if err := doc.Decode(cfg); err != nil {
+		return nil, nil, nil, util.NewError(util.ErrConfigInvalid, "Failed to parse configuration file", err)
+	}
+
+	lines, unknown := keyLines(&doc)
+	return cfg, lines, unknown, nil
+}
+
+// Error lists the problems, one per line
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].String()
+	}
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, strconv.Itoa(len(e.Problems))+" problems found")
+	for _, problem := range e.Problems {
+		lines = append(lines, "  - "+problem.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// String describes the problem along with its key and line
+func (p Problem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("%s (line %d): %s", p.Key, p.Line, p.Message)
+	}
+	return p.Key + ": " + p.Message
+}
+
+// problemsError turns the problems that aren't warnings into an ErrConfigInvalid error, or nil if there are none
+func problemsError(problems []Problem) error {
+	errors := make([]Problem, 0, len(problems))
+	for _, problem := range problems {
+		if !problem.Warning {
+			errors = append(errors, problem)
+		}
+	}
+	if len(errors) == 0 {
+		return nil
+	}
+	return util.NewError(util.ErrConfigInvalid, "Invalid configuration", &ValidationError{Problems: errors})
+}
+
+// validate checks every value of a configuration
+func validate(cfg *Config) []Problem {
+	problems := make([]Problem, 0)
+	add := func(key string, message string) {
+		problems = append(problems, Problem{Key: key, Message: message})
+	}
+
+	// Docker
+	if !slices.Contains(Runtimes, cfg.Docker.Runtime) {
+		add("docker.runtime", "unsupported container runtime "+strconv.Quote(cfg.Docker.Runtime)+", expected one of "+strings.Join(Runtimes, ", "))
+	}
+	if cfg.Docker.Sock != "" {
+		if _, err := os.Stat(cfg.Docker.Sock); err != nil {
+			problems = append(problems, Problem{Key: "docker.sock", Message: "socket " + cfg.Docker.Sock + " doesn't exist", Warning: true})
+		}
+	}
+	if !projectNamePattern.MatchString(cfg.Docker.ProjectName) {
+		add("docker.projectName", "must start with a lowercase letter or digit and only contain lowercase letters, digits, - and _")
+	}
+	if !networkNamePattern.MatchString(cfg.Docker.NetworkName) {
+		add("docker.networkName", "must start with a letter or digit and only contain letters, digits, ., - and _")
+	}
+
+	// Images
+	for key, image := range map[string]string{
+		"proxy.imageName": cfg.Proxy.ImageName,
+		"ssh.imageName":   cfg.SSH.ImageName,
+	} {
+		if !imageReferencePattern.MatchString(image) {
+			add(key, "invalid image reference "+strconv.Quote(image))
+		}
+	}
+
+	// Ports, which must not be shared by the services listening on TCP
+	usedPorts := make(map[int]string)
+	for _, port := range []struct {
+		key   string
+		value string
+		tcp   bool
+	}{
+		{"proxy.httpPort", cfg.Proxy.HTTPPort, true},
+		{"proxy.httpsPort", cfg.Proxy.HTTPSPort, true},
+		{"proxy.adminPort", cfg.Proxy.AdminPort, true},
+		{"ssh.port", cfg.SSH.Port, true},
+		{"dns.port", cfg.DNS.Port, false},
+	} {
+		number, err := strconv.Atoi(port.value)
+		if err != nil || number < 1 || number > 65535 {
+			add(port.key, "must be a port number between 1 and 65535, got "+strconv.Quote(port.value))
+			continue
+		}
+		if !port.tcp {
+			continue
+		}
+		if other, used := usedPorts[number]; used {
+			add(port.key, "port "+port.value+" is already used by "+other)
+			continue
+		}
+		usedPorts[number] = port.key
+	}
+
+	// DNS
+	if !isDomain(cfg.DNS.TLD) {
+		add("dns.tld", "must be a domain made of lowercase labels separated by dots, got "+strconv.Quote(cfg.DNS.TLD))
+	}
+	if cfg.DNS.Port == mdnsPort {
+		problems = append(problems, Problem{Key: "dns.port", Message: "port " + mdnsPort + " is used by mDNS (Avahi, Bonjour), the resolver may fail to start", Warning: true})
+	}
+	if cfg.DNS.Upstream != "" {
+		if _, port, err := net.SplitHostPort(cfg.DNS.Upstream); err != nil || port == "" {
+			add("dns.upstream", "must be an address with a port, e.g. 1.1.1.1:53")
+		}
+	}
+
+	// Hosts
+	if cfg.Hosts.File == "" {
+		add("hosts.file", "cannot be empty")
+	}
+
+	slices.SortStableFunc(problems, func(a Problem, b Problem) int {
+		return slices.Index(Keys(), a.Key) - slices.Index(Keys(), b.Key)
+	})
+	return problems
+}
+
+// keyLines maps the dotted keys of a configuration document to their line
+// Returns the lines along with the keys that aren't part of the configuration
+func keyLines(doc *yaml.Node) (map[string]int, []string) {
+	lines := make(map[string]int)
+	unknown := make([]string, 0)
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return lines, unknown
+	}
+	known := Keys()
+
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		section, value := root.Content[i], root.Content[i+1]
+		lines[section.Value] = section.Line
+		if section.Value == "version" {
+			continue
+		}
+		if value.Kind != yaml.MappingNode {
+			if !slices.ContainsFunc(known, func(key string) bool { return strings.HasPrefix(key, section.Value+".") }) {
+				unknown = append(unknown, section.Value)
+			}
+			continue
+		}
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			key := section.Value + "." + value.Content[j].Value
+			lines[key] = value.Content[j].Line
+			if !slices.Contains(known, key) {
+				unknown = append(unknown, key)
+			}
+		}
+	}
+	return lines, unknown
+}
+
+// isDomain checks if a value is a domain made of valid labels
+func isDomain(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, label := range strings.Split(value, ".") {
+		if !labelPattern.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
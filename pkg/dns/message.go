@@ -0,0 +1,112 @@
+// Package dns implements the small DNS resolver answering for Tulip's project hostnames
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// DNS constants used by the resolver, as defined in RFC 1035
+const (
+	headerSize = 12
+
+	typeA    = 1
+	typeAAAA = 28
+	classIN  = 1
+
+	flagQR = 1 << 15 // Message is a response
+	flagAA = 1 << 10 // Authoritative answer
+	flagRD = 1 << 8  // Recursion desired
+
+	rcodeSuccess  = 0 // No error
+	rcodeFormat   = 1 // Format error
+	rcodeNotImpl  = 4 // Not implemented
+	rcodeRefused  = 5 // Refused
+	opcodeMask    = 0x7800
+	opcodeQuery   = 0
+	maxLabelBytes = 63
+)
+
+// errMalformed is returned when a message can't be parsed
+var errMalformed = errors.New("malformed DNS message")
+
+// question represents the first question of a DNS query
+type question struct {
+	Name  string // Queried name, lowercased and without trailing dot
+	Type  uint16
+	Class uint16
+	end   int // Offset of the first byte after the question
+}
+
+// parseQuery decodes the header and first question of a DNS query
+func parseQuery(msg []byte) (uint16, uint16, question, error) {
+	if len(msg) < headerSize {
+		return 0, 0, question{}, errMalformed
+	}
+	id := binary.BigEndian.Uint16(msg[0:2])
+	flags := binary.BigEndian.Uint16(msg[2:4])
+	if binary.BigEndian.Uint16(msg[4:6]) == 0 {
+		return id, flags, question{}, errMalformed
+	}
+
+	// Read the labels of the queried name, compression isn't allowed in questions
+	labels := make([]string, 0)
+	offset := headerSize
+	for {
+		if offset >= len(msg) {
+			return id, flags, question{}, errMalformed
+		}
+		length := int(msg[offset])
+		offset++
+		if length == 0 {
+			break
+		}
+		if length > maxLabelBytes || offset+length > len(msg) {
+			return id, flags, question{}, errMalformed
+		}
+		labels = append(labels, string(msg[offset:offset+length]))
+		offset += length
+	}
+
+	if offset+4 > len(msg) {
+		return id, flags, question{}, errMalformed
+	}
+	q := question{
+		Name:  strings.ToLower(strings.Join(labels, ".")),
+		Type:  binary.BigEndian.Uint16(msg[offset : offset+2]),
+		Class: binary.BigEndian.Uint16(msg[offset+2 : offset+4]),
+		end:   offset + 4,
+	}
+	return id, flags, q, nil
+}
+
+// buildResponse creates a response to a query, echoing its first question
+// An A record pointing to ip is added when ip is not nil
+func buildResponse(query []byte, id uint16, queryFlags uint16, q *question, rcode uint16, ip []byte, ttl uint32) []byte {
+	flags := flagQR | flagAA | (queryFlags & (opcodeMask | flagRD)) | rcode
+
+	response := make([]byte, headerSize, 512)
+	binary.BigEndian.PutUint16(response[0:2], id)
+	binary.BigEndian.PutUint16(response[2:4], flags)
+	if q == nil {
+		return response
+	}
+
+	binary.BigEndian.PutUint16(response[4:6], 1)
+	if ip != nil {
+		binary.BigEndian.PutUint16(response[6:8], 1)
+	}
+	response = append(response, query[headerSize:q.end]...)
+
+	if ip != nil {
+		answer := make([]byte, 12)
+		binary.BigEndian.PutUint16(answer[0:2], 0xC000|headerSize) // Pointer to the question name
+		binary.BigEndian.PutUint16(answer[2:4], typeA)
+		binary.BigEndian.PutUint16(answer[4:6], classIN)
+		binary.BigEndian.PutUint32(answer[6:10], ttl)
+		binary.BigEndian.PutUint16(answer[10:12], uint16(len(ip)))
+		response = append(append(response, answer...), ip...)
+	}
+	return response
+}
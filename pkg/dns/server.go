@@ -0,0 +1,134 @@
+// Package dns implements the small DNS resolver answering for Tulip's project hostnames
+package dns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+const (
+	// TTL of the records served for project hostnames
+	recordTTL = 60
+	// Time allowed to the upstream resolver to answer a forwarded query
+	upstreamTimeout = 5 * time.Second
+	// Maximum size of a DNS message over UDP
+	maxMessageSize = 4096
+)
+
+// Server answers queries for a TLD with the loopback address
+// Other queries are forwarded to an upstream resolver, or refused if there is none
+type Server struct {
+	Addr     string // Address to listen on, e.g. 127.0.0.1:10053
+	TLD      string // Domain served by the resolver, e.g. tulip.test
+	Upstream string // Resolver to forward other queries to, e.g. 1.1.1.1:53
+}
+
+// ListenAndServe serves DNS queries over UDP until the context is cancelled
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", s.Addr)
+	if err != nil {
+		return util.HandleError("Failed to listen on "+s.Addr, err)
+	}
+	return s.Serve(ctx, conn)
+}
+
+// Serve answers DNS queries received on the given connection until the context is cancelled
+func (s *Server) Serve(ctx context.Context, conn net.PacketConn) error {
+	// Close the connection once the context is done to unblock reads
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buffer := make([]byte, maxMessageSize)
+	for {
+		n, addr, err := conn.ReadFrom(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			continue
+		}
+
+		query := make([]byte, n)
+		copy(query, buffer[:n])
+		go func() {
+			if response := s.handle(query); response != nil {
+				conn.WriteTo(response, addr)
+			}
+		}()
+	}
+}
+
+// handle builds the response to a single DNS query
+// Returns nil if the query should be dropped
+func (s *Server) handle(query []byte) []byte {
+	id, flags, q, err := parseQuery(query)
+	if err != nil {
+		if len(query) < headerSize {
+			return nil
+		}
+		return buildResponse(query, id, flags, nil, rcodeFormat, nil, 0)
+	}
+	if flags&flagQR != 0 {
+		return nil
+	}
+	if (flags&opcodeMask)>>11 != opcodeQuery {
+		return buildResponse(query, id, flags, nil, rcodeNotImpl, nil, 0)
+	}
+
+	if !s.isLocal(q.Name) {
+		if s.Upstream == "" {
+			return buildResponse(query, id, flags, &q, rcodeRefused, nil, 0)
+		}
+		response, err := s.forward(query)
+		if err != nil {
+			util.PrintWarning("Failed to forward query for " + q.Name + ": " + err.Error())
+			return buildResponse(query, id, flags, &q, rcodeRefused, nil, 0)
+		}
+		return response
+	}
+
+	// Only IPv4 addresses are served, other record types get an empty answer
+	if q.Class == classIN && q.Type == typeA {
+		return buildResponse(query, id, flags, &q, rcodeSuccess, net.IPv4(127, 0, 0, 1).To4(), recordTTL)
+	}
+	return buildResponse(query, id, flags, &q, rcodeSuccess, nil, 0)
+}
+
+// isLocal checks if a name belongs to the domain served by the resolver
+func (s *Server) isLocal(name string) bool {
+	tld := strings.ToLower(strings.Trim(s.TLD, "."))
+	return name == tld || strings.HasSuffix(name, "."+tld)
+}
+
+// forward relays a query to the upstream resolver and returns its response
+func (s *Server) forward(query []byte) ([]byte, error) {
+	conn, err := net.DialTimeout("udp", s.Upstream, upstreamTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(upstreamTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buffer := make([]byte, maxMessageSize)
+	n, err := conn.Read(buffer)
+	if err != nil {
+		return nil, err
+	}
+	return buffer[:n], nil
+}
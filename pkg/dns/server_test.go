@@ -0,0 +1,138 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startServer serves DNS queries on a random local port until the test ends
+func startServer(t *testing.T, server *Server) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ctx, conn) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve returned %v", err)
+		}
+	})
+	return conn.LocalAddr().String()
+}
+
+// exchange sends a raw message to the server and returns its response
+func exchange(t *testing.T, addr string, msg []byte) []byte {
+	t.Helper()
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.Write(msg); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	buffer := make([]byte, maxMessageSize)
+	n, err := conn.Read(buffer)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return buffer[:n]
+}
+
+// newQuery builds a query with recursion desired for a single question
+func newQuery(id uint16, name string, qtype uint16) []byte {
+	msg := make([]byte, headerSize)
+	binary.BigEndian.PutUint16(msg[0:2], id)
+	binary.BigEndian.PutUint16(msg[2:4], flagRD)
+	binary.BigEndian.PutUint16(msg[4:6], 1)
+	for _, label := range strings.Split(name, ".") {
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0)
+	msg = binary.BigEndian.AppendUint16(msg, qtype)
+	return binary.BigEndian.AppendUint16(msg, classIN)
+}
+
+func TestServe(t *testing.T) {
+	addr := startServer(t, &Server{TLD: "tulip.test"})
+
+	tests := []struct {
+		name    string
+		query   []byte
+		rcode   uint16
+		answers uint16
+		ip      net.IP
+	}{
+		{"A for project hostname", newQuery(1, "app.tulip.test", typeA), rcodeSuccess, 1, net.IPv4(127, 0, 0, 1)},
+		{"A for nested hostname in uppercase", newQuery(2, "API.App.Tulip.Test", typeA), rcodeSuccess, 1, net.IPv4(127, 0, 0, 1)},
+		{"A for the TLD itself", newQuery(3, "tulip.test", typeA), rcodeSuccess, 1, net.IPv4(127, 0, 0, 1)},
+		{"empty AAAA", newQuery(4, "app.tulip.test", typeAAAA), rcodeSuccess, 0, nil},
+		{"refused without upstream", newQuery(5, "example.com", typeA), rcodeRefused, 0, nil},
+		{"refused for lookalike domain", newQuery(6, "nottulip.test", typeA), rcodeRefused, 0, nil},
+		{"format error on truncated question", newQuery(7, "app.tulip.test", typeA)[:headerSize+5], rcodeFormat, 0, nil},
+		{"format error without question", newQuery(8, "app.tulip.test", typeA)[:headerSize], rcodeFormat, 0, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := exchange(t, addr, test.query)
+			if len(response) < headerSize {
+				t.Fatalf("response is %d bytes long", len(response))
+			}
+			if id := binary.BigEndian.Uint16(response[0:2]); id != binary.BigEndian.Uint16(test.query[0:2]) {
+				t.Errorf("id = %d, want %d", id, binary.BigEndian.Uint16(test.query[0:2]))
+			}
+			flags := binary.BigEndian.Uint16(response[2:4])
+			if flags&flagQR == 0 {
+				t.Errorf("QR flag isn't set")
+			}
+			if rcode := flags & 0xF; rcode != test.rcode {
+				t.Errorf("rcode = %d, want %d", rcode, test.rcode)
+			}
+			if answers := binary.BigEndian.Uint16(response[6:8]); answers != test.answers {
+				t.Errorf("answers = %d, want %d", answers, test.answers)
+			}
+			if test.ip != nil && !bytes.HasSuffix(response, test.ip.To4()) {
+				t.Errorf("response doesn't end with %v: % x", test.ip, response)
+			}
+		})
+	}
+}
+
+func TestServeForwardsToUpstream(t *testing.T) {
+	upstream := startServer(t, &Server{TLD: "example.com"})
+	addr := startServer(t, &Server{TLD: "tulip.test", Upstream: upstream})
+
+	response := exchange(t, addr, newQuery(9, "www.example.com", typeA))
+	if rcode := binary.BigEndian.Uint16(response[2:4]) & 0xF; rcode != rcodeSuccess {
+		t.Fatalf("rcode = %d, want %d", rcode, rcodeSuccess)
+	}
+	if answers := binary.BigEndian.Uint16(response[6:8]); answers != 1 {
+		t.Errorf("answers = %d, want 1", answers)
+	}
+}
+
+func TestServeDropsShortMessages(t *testing.T) {
+	addr := startServer(t, &Server{TLD: "tulip.test"})
+
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte{0, 1, 2})
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if n, err := conn.Read(make([]byte, maxMessageSize)); err == nil {
+		t.Errorf("got a %d bytes response to a message shorter than a header", n)
+	}
+}
@@ -0,0 +1,200 @@
+// Package hosts manages the block of Tulip entries kept in the hosts file
+package hosts
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// Markers fencing the block managed by Tulip
+const (
+	beginMarker = "# BEGIN tulip"
+	endMarker   = "# END tulip"
+)
+
+// Address the project hostnames point to
+const loopbackAddress = "127.0.0.1"
+
+// ErrUnterminatedBlock is returned when the hosts file opens a managed block without closing it
+var ErrUnterminatedBlock = errors.New("'" + beginMarker + "' has no matching '" + endMarker + "'")
+
+// Sync makes the managed block of the hosts file list exactly the given hostnames
+// Wildcard hostnames can't be expressed in a hosts file and are skipped
+// Returns true if the hosts file was changed
+func Sync(hostnames []string) (bool, error) {
+	entries := make([]string, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		if strings.Contains(hostname, "*") {
+			util.PrintWarning("Skipping wildcard hostname " + hostname + ", use the DNS resolver instead")
+			continue
+		}
+		entries = append(entries, strings.ToLower(hostname))
+	}
+	slices.Sort(entries)
+	entries = slices.Compact(entries)
+
+	return update(func(content string) (string, error) {
+		return Render(content, entries)
+	})
+}
+
+// Clean removes the managed block from the hosts file
+// Returns true if the hosts file was changed
+func Clean() (bool, error) {
+	return update(func(content string) (string, error) {
+		return Render(content, nil)
+	})
+}
+
+// List returns the hostnames currently listed in the managed block of the hosts file
+func List() ([]string, error) {
+	hostsFile, err := getHostsFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(hostsFile)
+	if err != nil {
+		return nil, util.HandleError("Failed to read hosts file "+hostsFile, err)
+	}
+	return Entries(string(content)), nil
+}
+
+// Render returns the hosts file content with its managed block replaced by the given hostnames
+// The block is removed altogether when there are no hostnames
+// Returns ErrUnterminatedBlock rather than dropping the lines following a block that isn't closed
+func Render(content string, hostnames []string) (string, error) {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	// Keep everything outside of the managed block
+	var output strings.Builder
+	inBlock := false
+	for _, line := range lines {
+		switch strings.TrimSpace(line) {
+		case beginMarker:
+			inBlock = true
+			continue
+		case endMarker:
+			inBlock = false
+			continue
+		}
+		if !inBlock {
+			output.WriteString(line)
+		}
+	}
+	if inBlock {
+		return "", ErrUnterminatedBlock
+	}
+
+	if len(hostnames) == 0 {
+		return output.String(), nil
+	}
+
+	// Append the managed block at the end of the file
+	if output.Len() > 0 && !strings.HasSuffix(output.String(), "\n") {
+		output.WriteString("\n")
+	}
+	output.WriteString(beginMarker + "\n")
+	for _, hostname := range hostnames {
+		output.WriteString(loopbackAddress + " " + hostname + "\n")
+	}
+	output.WriteString(endMarker + "\n")
+	return output.String(), nil
+}
+
+// Entries returns the hostnames listed in the managed block of the hosts file content
+func Entries(content string) []string {
+	hostnames := make([]string, 0)
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == beginMarker:
+			inBlock = true
+		case line == endMarker:
+			inBlock = false
+		case inBlock && line != "" && !strings.HasPrefix(line, "#"):
+			fields := strings.Fields(line)
+			hostnames = append(hostnames, fields[1:]...)
+		}
+	}
+	return hostnames
+}
+
+// update applies a change to the hosts file, only writing it when its content actually changes
+func update(change func(content string) (string, error)) (bool, error) {
+	hostsFile, err := getHostsFilePath()
+	if err != nil {
+		return false, err
+	}
+
+	content, err := os.ReadFile(hostsFile)
+	if err != nil && !os.IsNotExist(err) {
+		return false, util.HandleError("Failed to read hosts file "+hostsFile, err)
+	}
+
+	updated, err := change(string(content))
+	if errors.Is(err, ErrUnterminatedBlock) {
+		return false, util.HandleError("Failed to update hosts file "+hostsFile, err,
+			"Add the missing '"+endMarker+"' line by hand, then run the command again")
+	} else if err != nil {
+		return false, err
+	}
+	if updated == string(content) {
+		return false, nil
+	}
+
+	util.PrintInfo("Updating " + hostsFile + "..")
+	if err := write(hostsFile, []byte(updated)); err != nil {
+		return false, util.HandleError("Failed to write hosts file "+hostsFile, err)
+	}
+	util.PrintInfoReplace("Updated " + hostsFile)
+	return true, nil
+}
+
+// write replaces the content of the hosts file atomically, keeping its permissions
+// It escalates through sudo if the file isn't writable
+func write(hostsFile string, content []byte) error {
+	err := util.UpdateFileAtomic(hostsFile, content)
+	if errors.Is(err, syscall.EBUSY) {
+		// The file is a mount point, e.g. in a container, so it can't be replaced and is written in place instead
+		err = os.WriteFile(hostsFile, content, 0644)
+	}
+	if err == nil || !errors.Is(err, os.ErrPermission) || os.Geteuid() == 0 {
+		return err
+	}
+
+	// Overwrite the file in place to keep its ownership and permissions
+	var stderr bytes.Buffer
+	cmd := exec.Command("sudo", "tee", hostsFile)
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
+			return errors.New(errMsg)
+		}
+		return err
+	}
+	return nil
+}
+
+// getHostsFilePath returns the path of the hosts file from the configuration
+func getHostsFilePath() (string, error) {
+	// Get configuration
+	cfg, err := config.Get()
+	if err != nil {
+		return "", util.HandleError("Failed to load configuration", err)
+	}
+	return cfg.Hosts.File, nil
+}
@@ -0,0 +1,174 @@
+package hosts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+)
+
+// useHostsFile points the configuration to a temporary hosts file with the given content
+func useHostsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hosts")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Hosts.File = path
+	config.Set(cfg)
+	return path
+}
+
+// readFile returns the content of a file, failing the test if it can't be read
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		hostnames []string
+		want      string
+	}{
+		{
+			name:      "adds block to empty file",
+			hostnames: []string{"app.tulip.test"},
+			want:      "# BEGIN tulip\n127.0.0.1 app.tulip.test\n# END tulip\n",
+		},
+		{
+			name:      "adds block after file without trailing newline",
+			content:   "127.0.0.1 localhost",
+			hostnames: []string{"app.tulip.test"},
+			want:      "127.0.0.1 localhost\n# BEGIN tulip\n127.0.0.1 app.tulip.test\n# END tulip\n",
+		},
+		{
+			name:      "replaces block and keeps surrounding lines",
+			content:   "127.0.0.1 localhost\n# BEGIN tulip\n127.0.0.1 old.tulip.test\n# END tulip\n::1 localhost\n",
+			hostnames: []string{"a.tulip.test", "b.tulip.test"},
+			want:      "127.0.0.1 localhost\n::1 localhost\n# BEGIN tulip\n127.0.0.1 a.tulip.test\n127.0.0.1 b.tulip.test\n# END tulip\n",
+		},
+		{
+			name:    "removes block without hostnames",
+			content: "127.0.0.1 localhost\n# BEGIN tulip\n127.0.0.1 app.tulip.test\n# END tulip\n",
+			want:    "127.0.0.1 localhost\n",
+		},
+		{
+			name:    "leaves file without block untouched",
+			content: "127.0.0.1 localhost\n::1 localhost\n",
+			want:    "127.0.0.1 localhost\n::1 localhost\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Render(test.content, test.hostnames)
+			if err != nil {
+				t.Fatalf("Render returned %v", err)
+			}
+			if got != test.want {
+				t.Errorf("Render =\n%q\nwant\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRenderUnterminatedBlock(t *testing.T) {
+	content := "127.0.0.1 localhost\n# BEGIN tulip\n127.0.0.1 app.tulip.test\n10.0.0.1 intranet\n"
+	if _, err := Render(content, []string{"app.tulip.test"}); !errors.Is(err, ErrUnterminatedBlock) {
+		t.Errorf("Render returned %v, want ErrUnterminatedBlock", err)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	content := "127.0.0.1 localhost\n# BEGIN tulip\n127.0.0.1 a.tulip.test b.tulip.test\n# comment\n\n127.0.0.1 c.tulip.test\n# END tulip\n127.0.0.1 other\n"
+	want := []string{"a.tulip.test", "b.tulip.test", "c.tulip.test"}
+	if got := Entries(content); !slices.Equal(got, want) {
+		t.Errorf("Entries = %v, want %v", got, want)
+	}
+}
+
+func TestSyncAndClean(t *testing.T) {
+	path := useHostsFile(t, "127.0.0.1 localhost\n")
+
+	changed, err := Sync([]string{"B.tulip.test", "a.tulip.test", "*.tulip.test", "a.tulip.test"})
+	if err != nil || !changed {
+		t.Fatalf("Sync = %v, %v, want true, nil", changed, err)
+	}
+	want := "127.0.0.1 localhost\n# BEGIN tulip\n127.0.0.1 a.tulip.test\n127.0.0.1 b.tulip.test\n# END tulip\n"
+	if got := readFile(t, path); got != want {
+		t.Errorf("hosts file =\n%q\nwant\n%q", got, want)
+	}
+
+	hostnames, err := List()
+	if err != nil || !slices.Equal(hostnames, []string{"a.tulip.test", "b.tulip.test"}) {
+		t.Errorf("List = %v, %v", hostnames, err)
+	}
+
+	changed, err = Sync([]string{"a.tulip.test", "b.tulip.test"})
+	if err != nil || changed {
+		t.Errorf("second Sync = %v, %v, want false, nil", changed, err)
+	}
+
+	changed, err = Clean()
+	if err != nil || !changed {
+		t.Fatalf("Clean = %v, %v, want true, nil", changed, err)
+	}
+	if got := readFile(t, path); got != "127.0.0.1 localhost\n" {
+		t.Errorf("hosts file after Clean = %q", got)
+	}
+}
+
+func TestSyncKeepsFileWithUnterminatedBlock(t *testing.T) {
+	content := "127.0.0.1 localhost\n# BEGIN tulip\n127.0.0.1 app.tulip.test\n10.0.0.1 intranet\n"
+	path := useHostsFile(t, content)
+
+	if _, err := Sync([]string{"app.tulip.test"}); !errors.Is(err, ErrUnterminatedBlock) {
+		t.Errorf("Sync returned %v, want ErrUnterminatedBlock", err)
+	}
+	if got := readFile(t, path); got != content {
+		t.Errorf("hosts file was changed to %q", got)
+	}
+}
+
+func TestSyncReplacesFileAtomically(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "etc", "hosts")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("127.0.0.1 localhost\n"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	link := useHostsFile(t, "")
+	if err := os.Remove(link); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Sync([]string{"app.tulip.test"}); err != nil {
+		t.Fatalf("Sync returned %v", err)
+	}
+	if got := readFile(t, target); got != "127.0.0.1 localhost\n# BEGIN tulip\n127.0.0.1 app.tulip.test\n# END tulip\n" {
+		t.Errorf("hosts file = %q", got)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("symlink to the hosts file was replaced: %v", err)
+	}
+	if info, err := os.Stat(target); err != nil || info.Mode().Perm() != 0640 {
+		t.Errorf("hosts file mode = %v, %v, want 0640", info.Mode().Perm(), err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(target)); len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
@@ -0,0 +1,222 @@
+// Package project provides functionality for generating the Docker Compose file of a project
+package project
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Name of the network used by project services, mapped to Tulip's external network
+const networkAlias = "tulip-default"
+
+// composeFile mirrors the subset of the Docker Compose specification used by Tulip
+type composeFile struct {
+	Name     string                    `yaml:"name"`
+	Services map[string]composeService `yaml:"services"`
+	Networks map[string]composeNetwork `yaml:"networks"`
+	Volumes  map[string]struct{}       `yaml:"volumes,omitempty"`
+}
+
+// composeService represents a single service of a Docker Compose file
+type composeService struct {
+	Image         string            `yaml:"image"`
+	ContainerName string            `yaml:"container_name"`
+	Restart       string            `yaml:"restart"`
+	Command       string            `yaml:"command,omitempty"`
+	WorkingDir    string            `yaml:"working_dir,omitempty"`
+	Environment   map[string]string `yaml:"environment,omitempty"`
+	Volumes       []string          `yaml:"volumes,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Networks      []string          `yaml:"networks"`
+}
+
+// composeNetwork represents a network declaration of a Docker Compose file
+type composeNetwork struct {
+	Name     string `yaml:"name"`
+	External bool   `yaml:"external"`
+}
+
+// ComposeProjectName returns the Docker Compose project name used for the project
+func (p *Project) ComposeProjectName(cfg *config.Config) string {
+	return cfg.Docker.ProjectName + "-" + p.Manifest.Name
+}
+
+// ConfigDirPath returns the path of the directory holding the project's generated files
+func (p *Project) ConfigDirPath() string {
+	return config.GetProjectConfigDirPath(p.Manifest.Name)
+}
+
+// WriteComposeFile generates the project's docker-compose.yml in Tulip's containers directory
+// Returns the path of the directory holding the generated file
+func (p *Project) WriteComposeFile(cfg *config.Config) (string, error) {
+	projectConfigDirPath := p.ConfigDirPath()
+
+	// Create project directory if it doesn't exist
+	if err := os.MkdirAll(projectConfigDirPath, 0755); err != nil {
+		return "", util.HandleError("Failed to create project directory", err)
+	}
+
+	compose := p.buildCompose(cfg)
+	content, err := yaml.Marshal(compose)
+	if err != nil {
+		return "", util.HandleError("Failed to generate Docker Compose file", err)
+	}
+
+	// Create docker-compose.yml
+	dockerComposePath := filepath.Join(projectConfigDirPath, config.ProjectDockerComposeFile)
+	if err := util.CreateFile(dockerComposePath, content, util.FileOptions{}); err != nil {
+		return "", err
+	}
+
+	// Remember where the project lives
+	if err := p.writeState(); err != nil {
+		return "", err
+	}
+	return projectConfigDirPath, nil
+}
+
+// buildCompose assembles the Docker Compose representation of the project
+func (p *Project) buildCompose(cfg *config.Config) *composeFile {
+	compose := &composeFile{
+		Name:     p.ComposeProjectName(cfg),
+		Services: make(map[string]composeService),
+		Networks: map[string]composeNetwork{
+			networkAlias: {Name: cfg.Docker.NetworkName, External: true},
+		},
+		Volumes: make(map[string]struct{}),
+	}
+
+	for name, service := range p.services() {
+		volumes := make([]string, 0, len(service.Volumes))
+		for _, volume := range service.Volumes {
+			volume, named := p.resolveVolume(volume)
+			if named != "" {
+				compose.Volumes[named] = struct{}{}
+			}
+			volumes = append(volumes, volume)
+		}
+
+		// Expose the web service through the proxy
+		var labels map[string]string
+		if name == WebService {
+			labels = p.traefikLabels(cfg, service)
+		}
+
+		compose.Services[name] = composeService{
+			Image:         service.Image,
+			ContainerName: p.ComposeProjectName(cfg) + "-" + name,
+			Restart:       "unless-stopped",
+			Command:       service.Command,
+			WorkingDir:    service.WorkingDir,
+			Environment:   service.Environment,
+			Volumes:       volumes,
+			Labels:        labels,
+			Networks:      []string{networkAlias},
+		}
+	}
+
+	return compose
+}
+
+// services merges the default services of the project type with the ones declared in the manifest
+func (p *Project) services() map[string]Service {
+	services := make(map[string]Service)
+	for name, service := range typeServices(p.Manifest) {
+		services[name] = service
+	}
+
+	for name, declared := range p.Manifest.Services {
+		service, ok := services[name]
+		if !ok {
+			services[name] = declared
+			continue
+		}
+
+		// Declared values take precedence over the defaults of the project type
+		if declared.Image != "" {
+			service.Image = declared.Image
+		}
+		if declared.Command != "" {
+			service.Command = declared.Command
+		}
+		if declared.WorkingDir != "" {
+			service.WorkingDir = declared.WorkingDir
+		}
+		if declared.Port != 0 {
+			service.Port = declared.Port
+		}
+		if len(declared.Environment) > 0 {
+			environment := make(map[string]string)
+			for key, value := range service.Environment {
+				environment[key] = value
+			}
+			for key, value := range declared.Environment {
+				environment[key] = value
+			}
+			service.Environment = environment
+		}
+		service.Volumes = append(append([]string{}, service.Volumes...), declared.Volumes...)
+		services[name] = service
+	}
+
+	return services
+}
+
+// typeServices returns the default services provided by the project type
+func typeServices(manifest *Manifest) map[string]Service {
+	docroot := path.Clean(filepath.ToSlash(manifest.Docroot))
+
+	switch manifest.Type {
+	case TypeStatic:
+		return map[string]Service{
+			WebService: {
+				Image:   "nginx:alpine",
+				Volumes: []string{"./" + docroot + ":/usr/share/nginx/html:ro"},
+			},
+		}
+	case TypePHP:
+		return map[string]Service{
+			WebService: {
+				Image:       "webdevops/php-apache:8.3",
+				Environment: map[string]string{"WEB_DOCUMENT_ROOT": path.Join("/app", docroot)},
+				Volumes:     []string{".:/app"},
+			},
+		}
+	case TypeNode:
+		return map[string]Service{
+			WebService: {
+				Image:      "node:22-alpine",
+				Command:    "npm run dev",
+				WorkingDir: "/app",
+				Volumes:    []string{".:/app"},
+				Port:       3000,
+			},
+		}
+	}
+	return nil
+}
+
+// resolveVolume makes relative bind mounts absolute, since the Docker Compose file
+// lives outside of the project directory
+// Returns the resolved volume and the name of the volume if it is a named volume
+func (p *Project) resolveVolume(volume string) (string, string) {
+	source, target, found := strings.Cut(volume, ":")
+	if !found {
+		return volume, ""
+	}
+
+	switch {
+	case source == "." || strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../"):
+		return filepath.Join(p.Dir, source) + ":" + target, ""
+	case filepath.IsAbs(source) || strings.HasPrefix(source, "~"):
+		return volume, ""
+	default:
+		return volume, source
+	}
+}
@@ -0,0 +1,171 @@
+// Package project handles project-level operations in Tulip
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Project types supported by the manifest
+const (
+	TypeCustom = "custom" // No default service, everything is declared in the manifest
+	TypeStatic = "static" // Static files served by nginx
+	TypePHP    = "php"    // PHP application served by Apache
+	TypeNode   = "node"   // Node.js application started with npm
+)
+
+// WebService is the name of the service that receives the project's web traffic
+const WebService = "web"
+
+// Manifest represents the content of a project's .tulip.yml file
+type Manifest struct {
+	Name      string             `yaml:"name"`
+	Type      string             `yaml:"type"`
+	Docroot   string             `yaml:"docroot"`
+	Services  map[string]Service `yaml:"services"`
+	Hostnames []string           `yaml:"hostnames"`
+}
+
+// Service describes a container that is part of a project
+type Service struct {
+	Image       string            `yaml:"image"`
+	Command     string            `yaml:"command"`
+	WorkingDir  string            `yaml:"workingDir"`
+	Environment map[string]string `yaml:"environment"`
+	Volumes     []string          `yaml:"volumes"`
+	Port        int               `yaml:"port"` // Port on which the web service listens
+}
+
+// Project represents a Tulip project found on disk
+type Project struct {
+	Dir      string    // Absolute path to the project root
+	Manifest *Manifest // Parsed project manifest
+}
+
+// Names that cannot be used by projects because they collide with Tulip's own files
+var reservedNames = []string{config.ProxyConfigDir, config.SSHConfigDir}
+
+// Valid project names are usable both as a DNS label and as a directory name
+var namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// Load reads and validates the manifest found in the given directory
+func Load(dir string) (*Project, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, util.HandleError("Failed to resolve project directory", err)
+	}
+
+	manifestPath := filepath.Join(absDir, config.ProjectManifestFile)
+	manifestData, err := os.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return nil, util.HandleError("No "+config.ProjectManifestFile+" found in "+absDir, nil)
+	} else if err != nil {
+		return nil, util.HandleError("Failed to read project manifest", err)
+	}
+
+	manifest := &Manifest{}
+	if err := yaml.Unmarshal(manifestData, manifest); err != nil {
+		return nil, util.HandleError("Failed to parse project manifest "+manifestPath, err)
+	}
+
+	// Get configuration
+	cfg, err := config.Get()
+	if err != nil {
+		return nil, util.HandleError("Failed to load configuration", err)
+	}
+
+	applyManifestDefaults(manifest, absDir, cfg)
+
+	if err := validateManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	return &Project{Dir: absDir, Manifest: manifest}, nil
+}
+
+// applyManifestDefaults fills in the values that may be omitted from a manifest
+func applyManifestDefaults(manifest *Manifest, dir string, cfg *config.Config) {
+	if manifest.Name == "" {
+		manifest.Name = sanitizeName(filepath.Base(dir))
+	}
+	if manifest.Type == "" {
+		manifest.Type = TypeCustom
+	}
+	if manifest.Docroot == "" {
+		manifest.Docroot = "."
+	}
+	if len(manifest.Hostnames) == 0 {
+		manifest.Hostnames = []string{manifest.Name + "." + cfg.DNS.TLD}
+	}
+	if manifest.Services == nil {
+		manifest.Services = make(map[string]Service)
+	}
+}
+
+// validateManifest ensures the manifest has valid values
+func validateManifest(manifest *Manifest) error {
+	if err := validateName(manifest.Name); err != nil {
+		return err
+	}
+
+	switch manifest.Type {
+	case TypeCustom, TypeStatic, TypePHP, TypeNode:
+	default:
+		return util.HandleError("Unknown project type: "+manifest.Type, nil)
+	}
+
+	if filepath.IsAbs(manifest.Docroot) || strings.HasPrefix(filepath.Clean(manifest.Docroot), "..") {
+		return util.HandleError("Project docroot must be inside the project: "+manifest.Docroot, nil)
+	}
+
+	if manifest.Type == TypeCustom && len(manifest.Services) == 0 {
+		return util.HandleError("Project "+manifest.Name+" does not declare any service", nil)
+	}
+	for name, service := range manifest.Services {
+		if !namePattern.MatchString(name) {
+			return util.HandleError("Invalid service name: "+name, nil)
+		}
+		if service.Image == "" && (manifest.Type == TypeCustom || name != WebService) {
+			return util.HandleError("Service "+name+" must declare an image", nil)
+		}
+		if service.Port < 0 || service.Port > 65535 {
+			return util.HandleError("Invalid port for service "+name+": "+strconv.Itoa(service.Port), nil)
+		}
+	}
+
+	for _, hostname := range manifest.Hostnames {
+		if hostname == "" || strings.ContainsAny(hostname, " /:`") {
+			return util.HandleError("Invalid hostname: "+hostname, nil)
+		}
+	}
+
+	return nil
+}
+
+// validateName checks that a project name is usable as a directory name in Tulip's directory
+func validateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return util.HandleError("Invalid project name: "+name, nil,
+			"Names may only contain lowercase letters, digits and hyphens")
+	}
+	for _, reserved := range reservedNames {
+		if name == reserved {
+			return util.HandleError("Project name is reserved by Tulip: "+name, nil)
+		}
+	}
+	return nil
+}
+
+// sanitizeName turns a directory name into a valid project name
+func sanitizeName(name string) string {
+	name = strings.ToLower(name)
+	name = regexp.MustCompile(`[^a-z0-9-]+`).ReplaceAllString(name, "-")
+	return strings.Trim(name, "-")
+}
@@ -1,10 +1,292 @@
 // Package project handles project-level operations in Tulip
 package project
 
-import "github.com/pierrestoffe/tulip/pkg/util"
+import (
+	"os"
+	"strings"
 
-// Start begins the execution of a Tulip project in the current directory
-func Start() error {
-	util.PrintDebug("hello")
-	return nil
+	"github.com/pierrestoffe/tulip/pkg/certs"
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/runtime"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// Start launches the containers of a Tulip project if they're not already running
+// Returns true if the project was started, false if it was already running, and any error that occurred
+func Start(project *Project) (bool, error) {
+	// Get configuration
+	cfg, err := config.Get()
+	if err != nil {
+		return false, util.HandleError("Failed to load configuration", err)
+	}
+	name := project.Manifest.Name
+
+	// Check if the project is already running
+	paused, err := project.IsPaused(cfg)
+	if err != nil {
+		return false, err
+	}
+	if paused {
+		util.PrintWarning("Project " + name + " is paused, run 'tulip unpause' to resume it")
+		return false, nil
+	}
+	running, err := project.IsRunning(cfg)
+	if err != nil {
+		return false, err
+	}
+	if running {
+		util.PrintWarning("Project " + name + " is already running")
+		return false, nil
+	}
+
+	// Make sure the project hostnames are served over HTTPS
+	if _, err := certs.Ensure(name, project.Manifest.Hostnames); err != nil {
+		return false, err
+	}
+
+	// Generate the project's Docker Compose file
+	projectConfigDir, err := project.WriteComposeFile(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	util.PrintInfo("Starting " + name + " project..")
+
+	// Start the project containers
+	if err := runtime.Get().ComposeUp(project.composeProject(projectConfigDir, cfg)); err != nil {
+		return false, util.HandleError("Error starting "+name+" project", err)
+	}
+
+	util.PrintInfoReplace("Project " + name + " started")
+	_, httpsPort := cfg.PublishedPorts()
+	urls := make([]string, 0, len(project.Manifest.Hostnames))
+	for _, hostname := range project.Manifest.Hostnames {
+		if strings.HasPrefix(hostname, "*.") {
+			continue
+		}
+		if httpsPort != "443" {
+			hostname += ":" + httpsPort
+		}
+		urls = append(urls, "https://"+hostname)
+		util.PrintSuccess("Access the project: https://" + hostname)
+	}
+	util.AddResult("urls", urls)
+	return true, nil
+}
+
+// Stop halts the containers of a Tulip project without removing them
+// Returns true if the project was stopped, false if it wasn't running, and any error that occurred
+func Stop(project *Project) (bool, error) {
+	// Get configuration
+	cfg, err := config.Get()
+	if err != nil {
+		return false, util.HandleError("Failed to load configuration", err)
+	}
+	name := project.Manifest.Name
+
+	// Check if the project is running
+	running, err := project.IsRunning(cfg)
+	if err != nil {
+		return false, err
+	}
+	paused, err := project.IsPaused(cfg)
+	if err != nil {
+		return false, err
+	}
+	if !running && !paused {
+		util.PrintWarning("Project " + name + " is already stopped.")
+		return false, nil
+	}
+
+	util.PrintInfo("Stopping " + name + " project..")
+
+	// Stop the project containers
+	if err := runtime.Get().ComposeStop(project.composeProject(project.ConfigDirPath(), cfg)); err != nil {
+		return false, util.HandleError("Error stopping "+name+" project", err)
+	}
+
+	util.PrintInfoReplace("Project " + name + " was stopped")
+	return true, nil
+}
+
+// Restart performs a clean shutdown and restart of a Tulip project
+// Returns an error if either the stop or start operations fail
+func Restart(project *Project) error {
+	if _, err := Stop(project); err != nil {
+		return err
+	}
+	_, err := Start(project)
+	return err
+}
+
+// Ensure checks if the project is running and starts it if it's not
+func Ensure(project *Project) error {
+	// Get configuration
+	cfg, err := config.Get()
+	if err != nil {
+		return util.HandleError("Failed to load configuration", err)
+	}
+
+	running, err := project.IsRunning(cfg)
+	if err != nil || running {
+		return err
+	}
+	_, err = Start(project)
+	return err
+}
+
+// Pause suspends all processes of a running Tulip project
+// Returns true if the project was paused, false if it wasn't running, and any error that occurred
+func Pause(project *Project) (bool, error) {
+	// Get configuration
+	cfg, err := config.Get()
+	if err != nil {
+		return false, util.HandleError("Failed to load configuration", err)
+	}
+	name := project.Manifest.Name
+
+	// Check if the project is running
+	paused, err := project.IsPaused(cfg)
+	if err != nil {
+		return false, err
+	}
+	if paused {
+		util.PrintWarning("Project " + name + " is already paused")
+		return false, nil
+	}
+	running, err := project.IsRunning(cfg)
+	if err != nil {
+		return false, err
+	}
+	if !running {
+		util.PrintWarning("Project " + name + " is not running")
+		return false, nil
+	}
+
+	util.PrintInfo("Pausing " + name + " project..")
+
+	// Pause the project containers
+	if err := runtime.Get().ComposePause(project.composeProject(project.ConfigDirPath(), cfg)); err != nil {
+		return false, util.HandleError("Error pausing "+name+" project", err)
+	}
+
+	util.PrintInfoReplace("Project " + name + " was paused")
+	return true, nil
+}
+
+// Unpause resumes all processes of a paused Tulip project
+// Returns true if the project was resumed, false if it wasn't paused, and any error that occurred
+func Unpause(project *Project) (bool, error) {
+	// Get configuration
+	cfg, err := config.Get()
+	if err != nil {
+		return false, util.HandleError("Failed to load configuration", err)
+	}
+	name := project.Manifest.Name
+
+	// Check if the project is paused
+	paused, err := project.IsPaused(cfg)
+	if err != nil {
+		return false, err
+	}
+	if !paused {
+		util.PrintWarning("Project " + name + " is not paused")
+		return false, nil
+	}
+
+	util.PrintInfo("Resuming " + name + " project..")
+
+	// Unpause the project containers
+	if err := runtime.Get().ComposeUnpause(project.composeProject(project.ConfigDirPath(), cfg)); err != nil {
+		return false, util.HandleError("Error resuming "+name+" project", err)
+	}
+
+	util.PrintInfoReplace("Project " + name + " was resumed")
+	return true, nil
+}
+
+// Remove deletes the containers of a Tulip project along with its generated files
+// Named volumes are only deleted when removeVolumes is true
+// Returns true if the project was removed, false if there was nothing to remove, and any error that occurred
+func Remove(project *Project, removeVolumes bool) (bool, error) {
+	// Get configuration
+	cfg, err := config.Get()
+	if err != nil {
+		return false, util.HandleError("Failed to load configuration", err)
+	}
+	name := project.Manifest.Name
+
+	// Check if there is anything left to remove
+	exists, err := project.Exists(cfg)
+	if err != nil {
+		return false, err
+	}
+	_, statErr := os.Stat(project.ConfigDirPath())
+	if os.IsNotExist(statErr) && !exists {
+		util.PrintWarning("Project " + name + " is already removed.")
+		return false, nil
+	}
+
+	// Regenerate the Docker Compose file so that every service gets removed
+	projectConfigDir, err := project.WriteComposeFile(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	util.PrintInfo("Removing " + name + " project..")
+
+	// Remove the project containers
+	if err := runtime.Get().ComposeDown(project.composeProject(projectConfigDir, cfg), removeVolumes); err != nil {
+		return false, util.HandleError("Error removing "+name+" project", err)
+	}
+
+	// Remove the generated files
+	if err := os.RemoveAll(projectConfigDir); err != nil {
+		return false, util.HandleError("Failed to remove project directory "+projectConfigDir, err)
+	}
+	if _, err := certs.Remove(name); err != nil {
+		return false, err
+	}
+
+	util.PrintInfoReplace("Project " + name + " was removed")
+	return true, nil
+}
+
+// IsRunning checks if any container of the project is currently running
+func (p *Project) IsRunning(cfg *config.Config) (bool, error) {
+	return p.hasContainers(cfg, runtime.ContainerFilter{State: runtime.StateRunning})
+}
+
+// IsPaused checks if any container of the project is currently paused
+func (p *Project) IsPaused(cfg *config.Config) (bool, error) {
+	return p.hasContainers(cfg, runtime.ContainerFilter{State: runtime.StatePaused})
+}
+
+// Exists checks if any container of the project exists, whatever its state
+func (p *Project) Exists(cfg *config.Config) (bool, error) {
+	return p.hasContainers(cfg, runtime.ContainerFilter{All: true})
+}
+
+// hasContainers checks if the container runtime lists any container of the project matching the filter
+// Returns an error if the container runtime couldn't tell
+func (p *Project) hasContainers(cfg *config.Config, filter runtime.ContainerFilter) (bool, error) {
+	filter.Labels = map[string]string{runtime.LabelComposeProject: p.ComposeProjectName(cfg)}
+	containers, err := runtime.Get().ContainerList(filter)
+	if err != nil {
+		return false, util.HandleError("Failed to check the containers of project "+p.Manifest.Name, err)
+	}
+	return len(containers) > 0, nil
+}
+
+// composeProject describes the project's Compose project for the container runtime
+// Includes all necessary environment variables and working directory settings
+func (p *Project) composeProject(projectConfigDir string, cfg *config.Config) runtime.ComposeProject {
+	return runtime.ComposeProject{
+		Dir: projectConfigDir,
+		Env: map[string]string{
+			"DOCKER_SOCK":         cfg.Docker.Socket(),
+			"DOCKER_NETWORK_NAME": cfg.Docker.NetworkName,
+		},
+		RemoveOrphans: true,
+	}
 }
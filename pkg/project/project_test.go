@@ -0,0 +1,142 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/runtime"
+	"github.com/pierrestoffe/tulip/pkg/runtime/runtimetest"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// newProject creates a project from a manifest, with Tulip's files in a temporary directory
+// and an in-memory container runtime
+func newProject(t *testing.T, manifest string) (*Project, *runtimetest.Fake) {
+	t.Helper()
+	t.Setenv(config.EnvHome, t.TempDir())
+	config.Set(config.DefaultConfig())
+	fake := runtimetest.NewFake()
+	runtime.Set(fake)
+
+	dir := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(filepath.Join(dir, "public"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, config.ProjectManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load returned %v", err)
+	}
+	return p, fake
+}
+
+func TestLifecycle(t *testing.T) {
+	p, _ := newProject(t, "type: static\ndocroot: public\n")
+	cfg, _ := config.Get()
+	check := func(state func(cfg *config.Config) (bool, error)) bool {
+		t.Helper()
+		value, err := state(cfg)
+		if err != nil {
+			t.Fatalf("checking the project state returned %v", err)
+		}
+		return value
+	}
+
+	if started, err := Start(p); err != nil || !started {
+		t.Fatalf("Start = %v, %v, want true, nil", started, err)
+	}
+	if !check(p.IsRunning) {
+		t.Fatal("project isn't running after Start")
+	}
+	if _, err := os.Stat(filepath.Join(p.ConfigDirPath(), config.ProjectDockerComposeFile)); err != nil {
+		t.Errorf("Compose file wasn't written: %v", err)
+	}
+	if started, err := Start(p); err != nil || started {
+		t.Errorf("second Start = %v, %v, want false, nil", started, err)
+	}
+
+	if paused, err := Pause(p); err != nil || !paused {
+		t.Fatalf("Pause = %v, %v, want true, nil", paused, err)
+	}
+	if !check(p.IsPaused) || check(p.IsRunning) {
+		t.Error("project isn't paused after Pause")
+	}
+	if started, err := Start(p); err != nil || started {
+		t.Errorf("Start of paused project = %v, %v, want false, nil", started, err)
+	}
+	if resumed, err := Unpause(p); err != nil || !resumed {
+		t.Fatalf("Unpause = %v, %v, want true, nil", resumed, err)
+	}
+
+	if stopped, err := Stop(p); err != nil || !stopped {
+		t.Fatalf("Stop = %v, %v, want true, nil", stopped, err)
+	}
+	if check(p.IsRunning) || !check(p.Exists) {
+		t.Error("project should exist without running after Stop")
+	}
+	if err := Ensure(p); err != nil || !check(p.IsRunning) {
+		t.Errorf("Ensure = %v, project running: %v", err, check(p.IsRunning))
+	}
+
+	if removed, err := Remove(p, true); err != nil || !removed {
+		t.Fatalf("Remove = %v, %v, want true, nil", removed, err)
+	}
+	if check(p.Exists) {
+		t.Error("project still has containers after Remove")
+	}
+	if _, err := os.Stat(p.ConfigDirPath()); !os.IsNotExist(err) {
+		t.Errorf("project directory wasn't removed: %v", err)
+	}
+	if removed, err := Remove(p, true); err != nil || removed {
+		t.Errorf("second Remove = %v, %v, want false, nil", removed, err)
+	}
+}
+
+func TestRuntimeUnavailable(t *testing.T) {
+	p, fake := newProject(t, "type: static\ndocroot: public\n")
+	fake.Err = fmt.Errorf("%w: connection refused", util.ErrRuntimeUnavailable)
+
+	operations := map[string]func() error{
+		"Start":   func() error { _, err := Start(p); return err },
+		"Stop":    func() error { _, err := Stop(p); return err },
+		"Pause":   func() error { _, err := Pause(p); return err },
+		"Unpause": func() error { _, err := Unpause(p); return err },
+		"Remove":  func() error { _, err := Remove(p, false); return err },
+		"Ensure":  func() error { return Ensure(p) },
+	}
+	for name, operation := range operations {
+		if err := operation(); !errors.Is(err, util.ErrRuntimeUnavailable) {
+			t.Errorf("%s returned %v, want ErrRuntimeUnavailable", name, err)
+		}
+	}
+	if len(fake.Calls) > 0 {
+		t.Errorf("calls = %v, want none", fake.Calls)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	p, _ := newProject(t, "name: demo\ntype: static\ndocroot: public\n")
+	if _, err := Start(p); err != nil {
+		t.Fatalf("Start returned %v", err)
+	}
+
+	found, err := Lookup("demo")
+	if err != nil {
+		t.Fatalf("Lookup returned %v", err)
+	}
+	if found.Dir != p.Dir {
+		t.Errorf("Lookup found %s, want %s", found.Dir, p.Dir)
+	}
+
+	for _, name := range []string{"unknown", "../demo", "demo/../../..", config.ProxyConfigDir} {
+		if _, err := Lookup(name); err == nil {
+			t.Errorf("Lookup(%q) succeeded", name)
+		}
+	}
+}
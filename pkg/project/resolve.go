@@ -0,0 +1,145 @@
+// Package project provides functionality for locating Tulip projects on disk
+package project
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvProject is the environment variable used to select a project when no flag is given
+const EnvProject = "TULIP_PROJECT"
+
+// state is the content of the file recording where a project lives on disk
+type state struct {
+	Dir string `yaml:"dir"`
+}
+
+// Resolve finds the project targeted by a command
+// The project is selected, in order of precedence, by the given flag value,
+// the TULIP_PROJECT environment variable or the current directory.
+// Flag and environment values may be either a path inside a project or the name of a known project.
+func Resolve(selector string) (*Project, error) {
+	if selector == "" {
+		selector = os.Getenv(EnvProject)
+	}
+
+	if selector == "" {
+		workingDir, err := os.Getwd()
+		if err != nil {
+			return nil, util.HandleError("Failed to get current directory", err)
+		}
+		return Find(workingDir)
+	}
+
+	// Paths take precedence over project names
+	if info, err := os.Stat(selector); err == nil && info.IsDir() {
+		return Find(selector)
+	}
+	return Lookup(selector)
+}
+
+// Find walks up from the given directory until it finds a project manifest
+func Find(dir string) (*Project, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, util.HandleError("Failed to resolve project directory", err)
+	}
+
+	for current := absDir; ; current = filepath.Dir(current) {
+		manifestPath := filepath.Join(current, config.ProjectManifestFile)
+		if _, err := os.Stat(manifestPath); err == nil {
+			return Load(current)
+		} else if !os.IsNotExist(err) {
+			return nil, util.HandleError("Error accessing file: "+manifestPath, err)
+		}
+
+		// Stop once the filesystem root has been reached
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	return nil, util.HandleError("No "+config.ProjectManifestFile+" found in "+absDir+" or any parent directory", nil)
+}
+
+// Lookup loads a project that was previously started by Tulip from its name
+// The name is validated first so that it can't point outside of Tulip's containers directory
+func Lookup(name string) (*Project, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	dir, err := readState(config.GetProjectConfigDirPath(name))
+	if os.IsNotExist(err) {
+		return nil, util.HandleError("Unknown project: "+name, nil)
+	} else if err != nil {
+		return nil, util.HandleError("Failed to read state of project "+name, err)
+	}
+	return Load(dir)
+}
+
+// writeState records where the project lives so that it can be found by name later on
+func (p *Project) writeState() error {
+	content, err := yaml.Marshal(state{Dir: p.Dir})
+	if err != nil {
+		return util.HandleError("Failed to generate project state", err)
+	}
+
+	statePath := filepath.Join(p.ConfigDirPath(), config.ProjectStateFile)
+	if err := os.WriteFile(statePath, content, 0644); err != nil {
+		return util.HandleError("Failed to write project state", err)
+	}
+	return nil
+}
+
+// readState returns the project directory recorded in a project configuration directory
+func readState(projectConfigDir string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(projectConfigDir, config.ProjectStateFile))
+	if err != nil {
+		return "", err
+	}
+
+	projectState := state{}
+	if err := yaml.Unmarshal(content, &projectState); err != nil {
+		return "", err
+	}
+	return projectState.Dir, nil
+}
+
+// List loads every project previously started by Tulip
+// Projects that can no longer be loaded are skipped with a warning
+func List() ([]*Project, error) {
+	entries, err := os.ReadDir(config.GetContainersConfigDirPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, util.HandleError("Failed to list projects", err)
+	}
+
+	projects := make([]*Project, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		// Skip Tulip's own containers, which have no project state
+		dir, err := readState(filepath.Join(config.GetContainersConfigDirPath(), entry.Name()))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			util.PrintWarning("Skipping project " + entry.Name() + ": " + err.Error())
+			continue
+		}
+
+		project, err := Load(dir)
+		if err != nil {
+			util.PrintWarning("Skipping project " + entry.Name())
+			continue
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
@@ -0,0 +1,62 @@
+// Package project provides functionality for routing project traffic through Traefik
+package project
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+)
+
+// Default port on which the web service is expected to listen
+const defaultWebPort = 80
+
+// traefikLabels returns the labels that let Traefik route the project hostnames to the web service
+func (p *Project) traefikLabels(cfg *config.Config, service Service) map[string]string {
+	router := p.ComposeProjectName(cfg)
+	port := service.Port
+	if port == 0 {
+		port = defaultWebPort
+	}
+
+	rules := make([]string, 0, len(p.Manifest.Hostnames))
+	for _, hostname := range p.Manifest.Hostnames {
+		rules = append(rules, hostRule(hostname))
+	}
+	rule := strings.Join(rules, " || ")
+
+	labels := map[string]string{
+		"traefik.enable":         "true",
+		"traefik.docker.network": cfg.Docker.NetworkName,
+
+		// HTTPS router
+		"traefik.http.routers." + router + ".rule":        rule,
+		"traefik.http.routers." + router + ".entrypoints": "websecure",
+		"traefik.http.routers." + router + ".tls":         "true",
+		"traefik.http.routers." + router + ".service":     router,
+
+		// HTTP router redirecting to HTTPS
+		"traefik.http.routers." + router + "-http.rule":                       rule,
+		"traefik.http.routers." + router + "-http.entrypoints":                "web",
+		"traefik.http.routers." + router + "-http.middlewares":                router + "-https",
+		"traefik.http.middlewares." + router + "-https.redirectscheme.scheme": "https",
+
+		// Port on which the web service listens
+		"traefik.http.services." + router + ".loadbalancer.server.port": strconv.Itoa(port),
+	}
+
+	// Browsers must be sent to the port the proxy publishes for HTTPS, which rootless runtimes may remap
+	if _, httpsPort := cfg.PublishedPorts(); httpsPort != "443" {
+		labels["traefik.http.middlewares."+router+"-https.redirectscheme.port"] = httpsPort
+	}
+	return labels
+}
+
+// hostRule converts a hostname into a Traefik rule, turning wildcards into regular expressions
+func hostRule(hostname string) string {
+	if suffix, found := strings.CutPrefix(hostname, "*."); found {
+		return "HostRegexp(`^[^.]+\\." + regexp.QuoteMeta(suffix) + "$`)"
+	}
+	return "Host(`" + hostname + "`)"
+}
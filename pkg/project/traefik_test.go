@@ -0,0 +1,33 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+)
+
+func TestTraefikLabelsRedirectPort(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	p := &Project{Manifest: &Manifest{Name: "demo", Hostnames: []string{"demo.tulip.test"}}}
+
+	tests := []struct {
+		httpsPort string
+		want      string // Port of the redirection, empty if the default one is used
+	}{
+		{"443", ""},
+		{"8443", "8443"},
+	}
+	for _, test := range tests {
+		cfg := config.DefaultConfig()
+		cfg.Proxy.HTTPSPort = test.httpsPort
+		router := p.ComposeProjectName(cfg)
+
+		labels := p.traefikLabels(cfg, Service{})
+		if got := labels["traefik.http.middlewares."+router+"-https.redirectscheme.port"]; got != test.want {
+			t.Errorf("redirect port with HTTPS on %s = %q, want %q", test.httpsPort, got, test.want)
+		}
+		if got := labels["traefik.http.middlewares."+router+"-https.redirectscheme.scheme"]; got != "https" {
+			t.Errorf("redirect scheme = %q, want https", got)
+		}
+	}
+}
@@ -2,13 +2,11 @@
 package container
 
 import (
-	"bytes"
 	"net"
-	"os"
-	"os/exec"
-	"strings"
+	"strconv"
 
 	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/runtime"
 	"github.com/pierrestoffe/tulip/pkg/util"
 )
 
@@ -21,11 +19,22 @@ func Start() (bool, error) {
 	}
 
 	// Check if the proxy container is already running
-	if IsRunning() {
+	running, err := IsRunning()
+	if err != nil {
+		return false, err
+	}
+	if running {
 		util.PrintWarning("Proxy " + config.ProxyContainerName + " is already running")
 		return false, nil
 	}
 
+	// Warn when privileged ports had to be replaced
+	httpPort, httpsPort := cfg.PublishedPorts()
+	if httpPort != cfg.Proxy.HTTPPort || httpsPort != cfg.Proxy.HTTPSPort {
+		util.PrintWarning("Rootless " + cfg.Docker.Runtime + " can't bind ports below " + strconv.Itoa(config.UnprivilegedPortStart()))
+		util.PrintWarning("Proxy will listen on ports " + httpPort + " (HTTP) and " + httpsPort + " (HTTPS) instead")
+	}
+
 	// Verify that all used ports are open
 	if err := verifyPorts(); err != nil {
 		return false, err
@@ -40,16 +49,8 @@ func Start() (bool, error) {
 	}
 
 	// Start the proxy container
-	cmd := prepareDockerComposeCmd("docker", []string{"compose", "up", "-d"}, proxyConfigDir, cfg)
-
-	// Capture stderr
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	// Run command and handle errors
-	if err := cmd.Run(); err != nil {
-		errMsg := stderr.String()
-		return false, util.HandleError("Error starting "+config.ProxyContainerName+" proxy", err, errMsg)
+	if err := runtime.Get().ComposeUp(prepareComposeProject(proxyConfigDir)); err != nil {
+		return false, util.HandleError("Error starting "+config.ProxyContainerName+" proxy", err)
 	}
 
 	util.PrintInfoReplace("Proxy " + config.ProxyContainerName + " started")
@@ -58,14 +59,12 @@ func Start() (bool, error) {
 
 // Terminates the proxy container if it's running
 func Stop() (bool, error) {
-	// Get configuration
-	cfg, err := config.Get()
+	// Check if the proxy container is running
+	running, err := IsRunning()
 	if err != nil {
-		return false, util.HandleError("Failed to load configuration", err)
+		return false, err
 	}
-
-	// Check if the proxy container is running
-	if !IsRunning() {
+	if !running {
 		util.PrintWarning("Proxy " + config.ProxyContainerName + " is already stopped.")
 		return false, nil
 	}
@@ -79,16 +78,8 @@ func Stop() (bool, error) {
 	}
 
 	// Stop the proxy container
-	cmd := prepareDockerComposeCmd("docker", []string{"compose", "down", "--remove-orphans"}, proxyConfigDir, cfg)
-
-	// Capture stderr
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	// Run command and handle errors
-	if err := cmd.Run(); err != nil {
-		errMsg := stderr.String()
-		return false, util.HandleError("Error stopping "+config.ProxyContainerName+" proxy", err, errMsg)
+	if err := runtime.Get().ComposeDown(prepareComposeProject(proxyConfigDir), false); err != nil {
+		return false, util.HandleError("Error stopping "+config.ProxyContainerName+" proxy", err)
 	}
 
 	util.PrintInfoReplace("Proxy " + config.ProxyContainerName + " was stopped")
@@ -97,22 +88,22 @@ func Stop() (bool, error) {
 
 // Ensure checks if the proxy container is running and starts it if it's not
 func Ensure() error {
-	if IsRunning() {
-		return nil
+	running, err := IsRunning()
+	if err != nil || running {
+		return err
 	}
-	_, err := Start()
+	_, err = Start()
 	return err
 }
 
 // Checks if the proxy container is currently running
-func IsRunning() bool {
-	cmd := exec.Command("docker", "ps", "--filter", "name="+config.ProxyContainerName, "--format", "{{.Names}}")
-	output, err := cmd.Output()
+// Returns an error if the container runtime couldn't tell
+func IsRunning() (bool, error) {
+	containers, err := runtime.Get().ContainerList(runtime.ContainerFilter{Name: config.ProxyContainerName})
 	if err != nil {
-		util.HandleError("Failed to check if container is running", err)
-		return false
+		return false, util.HandleError("Failed to check if proxy "+config.ProxyContainerName+" is running", err)
 	}
-	return len(strings.TrimSpace(string(output))) > 0
+	return len(containers) > 0, nil
 }
 
 // Checks if the required ports specified in the configuration are available.
@@ -124,16 +115,17 @@ func verifyPorts() error {
 	}
 
 	// Check required ports
+	httpPort, httpsPort := cfg.PublishedPorts()
 	requiredPorts := []string{
-		cfg.Proxy.HTTPPort,
-		cfg.Proxy.HTTPSPort,
+		httpPort,
+		httpsPort,
 		cfg.Proxy.AdminPort,
 		cfg.SSH.Port,
 	}
 
 	for _, port := range requiredPorts {
 		if isPortOpen(port) {
-			return util.HandleError("Port is already in use: "+port, nil)
+			return util.NewError(util.ErrPortInUse, "Port is already in use: "+port, nil)
 		}
 	}
 	return nil
@@ -150,20 +142,13 @@ func isPortOpen(port string) bool {
 	return true
 }
 
-// prepareDockerComposeCmd creates a properly configured exec.Cmd for Docker Compose operations
-// Includes all necessary environment variables and working directory settings
-func prepareDockerComposeCmd(command string, cmdArgs []string, proxyConfigDir string, cfg *config.Config) *exec.Cmd {
-	cmd := exec.Command(command, cmdArgs...)
-	cmd.Dir = proxyConfigDir
-	cmd.Env = os.Environ()
-	cmd.Env = append(cmd.Env, "COMPOSE_IGNORE_ORPHANS=1")
-	cmd.Env = append(cmd.Env, "DOCKER_SOCK="+cfg.Docker.Sock)
-	cmd.Env = append(cmd.Env, "DOCKER_PROJECT_NAME="+cfg.Docker.ProjectName)
-	cmd.Env = append(cmd.Env, "DOCKER_NETWORK_NAME="+cfg.Docker.NetworkName)
-	cmd.Env = append(cmd.Env, "DOCKER_IMAGE_PROXY="+cfg.Proxy.ImageName)
-	cmd.Env = append(cmd.Env, "HTTP_PORT="+cfg.Proxy.HTTPPort)
-	cmd.Env = append(cmd.Env, "HTTPS_PORT="+cfg.Proxy.HTTPSPort)
-	cmd.Env = append(cmd.Env, "ADMIN_PORT="+cfg.Proxy.AdminPort)
-
-	return cmd
+// prepareComposeProject describes the proxy's Compose project for the container runtime
+// Every value is rendered into the generated files, so the Compose file needs no variables
+func prepareComposeProject(proxyConfigDir string) runtime.ComposeProject {
+	return runtime.ComposeProject{
+		Dir: proxyConfigDir,
+		Env: map[string]string{
+			"COMPOSE_IGNORE_ORPHANS": "1",
+		},
+	}
 }
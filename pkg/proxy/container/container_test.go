@@ -0,0 +1,119 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/runtime"
+	"github.com/pierrestoffe/tulip/pkg/runtime/runtimetest"
+	proxySetup "github.com/pierrestoffe/tulip/pkg/setup/proxy"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// freePort returns a local TCP port nothing listens on
+func freePort(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+}
+
+// useFake runs the test against an in-memory runtime, with the proxy files generated in a temporary directory
+func useFake(t *testing.T) *runtimetest.Fake {
+	t.Helper()
+	t.Setenv(config.EnvHome, t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.Proxy.HTTPPort = freePort(t)
+	cfg.Proxy.HTTPSPort = freePort(t)
+	cfg.Proxy.AdminPort = freePort(t)
+	cfg.SSH.Port = freePort(t)
+	config.Set(cfg)
+	if err := proxySetup.Initialize(); err != nil {
+		t.Fatalf("Initialize returned %v", err)
+	}
+
+	fake := runtimetest.NewFake()
+	runtime.Set(fake)
+	return fake
+}
+
+// isRunning checks if the proxy container is running, failing the test if the runtime couldn't tell
+func isRunning(t *testing.T) bool {
+	t.Helper()
+	running, err := IsRunning()
+	if err != nil {
+		t.Fatalf("IsRunning returned %v", err)
+	}
+	return running
+}
+
+func TestStartStop(t *testing.T) {
+	fake := useFake(t)
+	proxyDir := config.GetProxyConfigDirPath()
+
+	if started, err := Start(); err != nil || !started {
+		t.Fatalf("Start = %v, %v, want true, nil", started, err)
+	}
+	if !isRunning(t) {
+		t.Error("proxy isn't running after Start")
+	}
+	if started, err := Start(); err != nil || started {
+		t.Errorf("second Start = %v, %v, want false, nil", started, err)
+	}
+
+	if stopped, err := Stop(); err != nil || !stopped {
+		t.Fatalf("Stop = %v, %v, want true, nil", stopped, err)
+	}
+	if isRunning(t) {
+		t.Error("proxy is running after Stop")
+	}
+
+	want := []string{"ComposeUp " + proxyDir, "ComposeDown " + proxyDir + " volumes=false"}
+	if !slices.Equal(fake.Calls, want) {
+		t.Errorf("calls = %v, want %v", fake.Calls, want)
+	}
+}
+
+func TestStartPortInUse(t *testing.T) {
+	fake := useFake(t)
+	cfg, _ := config.Get()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:"+cfg.Proxy.AdminPort)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	if _, err := Start(); !errors.Is(err, util.ErrPortInUse) {
+		t.Errorf("Start returned %v, want ErrPortInUse", err)
+	}
+	if len(fake.Calls) != 0 {
+		t.Errorf("calls = %v, want none", fake.Calls)
+	}
+}
+
+func TestRuntimeUnavailable(t *testing.T) {
+	fake := useFake(t)
+	fake.Err = fmt.Errorf("%w: connection refused", util.ErrRuntimeUnavailable)
+
+	if _, err := Start(); !errors.Is(err, util.ErrRuntimeUnavailable) {
+		t.Errorf("Start returned %v, want ErrRuntimeUnavailable", err)
+	}
+	if _, err := Stop(); !errors.Is(err, util.ErrRuntimeUnavailable) {
+		t.Errorf("Stop returned %v, want ErrRuntimeUnavailable", err)
+	}
+	if err := Ensure(); !errors.Is(err, util.ErrRuntimeUnavailable) {
+		t.Errorf("Ensure returned %v, want ErrRuntimeUnavailable", err)
+	}
+	if len(fake.Calls) > 0 {
+		t.Errorf("calls = %v, want none", fake.Calls)
+	}
+}
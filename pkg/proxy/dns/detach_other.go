@@ -0,0 +1,9 @@
+//go:build !unix
+
+// Package dns manages the lifecycle of Tulip's background DNS resolver
+package dns
+
+import "os/exec"
+
+// detach is a no-op on platforms without sessions
+func detach(cmd *exec.Cmd) {}
@@ -0,0 +1,14 @@
+//go:build unix
+
+// Package dns manages the lifecycle of Tulip's background DNS resolver
+package dns
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach runs the command in its own session so that it outlives the terminal
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,234 @@
+// Package dns manages the lifecycle of Tulip's background DNS resolver
+package dns
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// Time allowed to the resolver to start listening, and to exit once told to
+const (
+	startTimeout = 3 * time.Second
+	stopTimeout  = 3 * time.Second
+)
+
+// errLocked is returned when another process holds the lock of a file
+var errLocked = errors.New("file is locked by another process")
+
+// Start launches the DNS resolver in the background if it's not already running
+// Returns true if the resolver was started, false if it was already running or is disabled, and any error that occurred
+func Start() (bool, error) {
+	// Get configuration
+	cfg, err := config.Get()
+	if err != nil {
+		return false, util.HandleError("Failed to load configuration", err)
+	}
+	if !cfg.DNS.Enabled {
+		return false, nil
+	}
+
+	// Check if the resolver is already running
+	if IsRunning() {
+		util.PrintWarning("DNS resolver is already running")
+		return false, nil
+	}
+
+	util.PrintInfo("Starting DNS resolver for *." + cfg.DNS.TLD + "..")
+
+	// Run the resolver from the current executable
+	executable, err := os.Executable()
+	if err != nil {
+		return false, util.HandleError("Failed to locate the "+config.AppName+" executable", err)
+	}
+	if err := os.MkdirAll(config.GetStateDirPath(), 0755); err != nil {
+		return false, util.HandleError("Failed to create state directory", err)
+	}
+	logFile, err := os.OpenFile(getLogFilePath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return false, util.HandleError("Failed to open DNS resolver log file", err)
+	}
+	defer logFile.Close()
+
+	// The resolver must use the same configuration as this command
+	cmd := exec.Command(executable, append([]string{"dns", "serve"}, config.OverrideArgs()...)...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return false, util.HandleError("Error starting DNS resolver", err)
+	}
+	// The resolver records its own process ID, see Claim
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	// Wait for the resolver to be ready
+	if err := waitForListener(cfg, exited, startTimeout); err != nil {
+		return false, util.HandleError("Error starting DNS resolver", err, "See "+getLogFilePath()+" for details")
+	}
+
+	util.PrintInfoReplace("DNS resolver started on 127.0.0.1:" + cfg.DNS.Port)
+	return true, nil
+}
+
+// Stop terminates the background DNS resolver if it's running
+// Returns true if the resolver was stopped, false if it wasn't running, and any error that occurred
+func Stop() (bool, error) {
+	pid, running := runningPid()
+	if !running {
+		// Whatever process has the recorded ID now, it isn't the resolver
+		os.Remove(getPidFilePath())
+		return false, nil
+	}
+
+	util.PrintInfo("Stopping DNS resolver..")
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false, util.HandleError("Error stopping DNS resolver", err)
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		return false, util.HandleError("Error stopping DNS resolver", err)
+	}
+
+	// The resolver removes its PID file when it exits
+	for deadline := time.Now().Add(stopTimeout); IsRunning(); time.Sleep(50 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			return false, util.NewError(nil, "DNS resolver didn't exit after "+stopTimeout.String(), nil,
+				"Stop process "+strconv.Itoa(pid)+" by hand")
+		}
+	}
+
+	util.PrintInfoReplace("DNS resolver was stopped")
+	return true, nil
+}
+
+// Ensure checks if the DNS resolver is running and starts it if it's not
+func Ensure() error {
+	if IsRunning() {
+		return nil
+	}
+	_, err := Start()
+	return err
+}
+
+// IsRunning checks if the background DNS resolver process is alive
+func IsRunning() bool {
+	_, running := runningPid()
+	return running
+}
+
+// Claim records the current process as the resolver in the PID file, which stays locked until the process exits
+// The lock tells the resolver apart from an unrelated process that got the recorded ID, e.g. after a reboot
+// Returns a function releasing the claim, or an ErrPortInUse error if another resolver is running
+func Claim() (func(), error) {
+	if err := os.MkdirAll(config.GetStateDirPath(), 0755); err != nil {
+		return nil, util.HandleError("Failed to create state directory", err)
+	}
+	file, err := os.OpenFile(getPidFilePath(), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, util.HandleError("Failed to open DNS resolver PID file", err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		if errors.Is(err, errLocked) {
+			return nil, util.NewError(util.ErrPortInUse, "DNS resolver is already running", nil, "Run 'tulip proxy stop' to stop it")
+		}
+		return nil, util.HandleError("Failed to lock DNS resolver PID file", err)
+	}
+
+	if err := file.Truncate(0); err != nil {
+		file.Close()
+		return nil, util.HandleError("Failed to write DNS resolver PID file", err)
+	}
+	if _, err := file.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0); err != nil {
+		file.Close()
+		return nil, util.HandleError("Failed to write DNS resolver PID file", err)
+	}
+	return func() {
+		os.Remove(getPidFilePath())
+		file.Close()
+	}, nil
+}
+
+// runningPid returns the process ID of the running resolver
+// The recorded ID only counts while the resolver holds the PID file locked
+func runningPid() (int, bool) {
+	file, err := os.Open(getPidFilePath())
+	if err != nil {
+		return 0, false
+	}
+	defer file.Close()
+
+	if locked, err := isLocked(file); err != nil || !locked {
+		return 0, false
+	}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil || !isAlive(pid) {
+		return 0, false
+	}
+	return pid, true
+}
+
+// isAlive checks if a process with the given ID exists
+func isAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// waitForListener waits until the resolver answers queries, exits or the timeout expires
+func waitForListener(cfg *config.Config, exited <-chan error, timeout time.Duration) error {
+	addr := net.JoinHostPort("127.0.0.1", cfg.DNS.Port)
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network string, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "udp", addr)
+		},
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		_, err := resolver.LookupIPAddr(ctx, cfg.DNS.TLD)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-exited:
+			return errors.New("DNS resolver exited unexpectedly")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return errors.New("DNS resolver did not start answering on " + addr)
+}
+
+// getPidFilePath constructs the full path to the resolver's PID file
+func getPidFilePath() string {
+	return filepath.Join(config.GetStateDirPath(), config.DNSPidFile)
+}
+
+// getLogFilePath constructs the full path to the resolver's log file
+func getLogFilePath() string {
+	return filepath.Join(config.GetStateDirPath(), config.DNSLogFile)
+}
@@ -0,0 +1,120 @@
+package dns
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// Environment variable making the test binary act as a resolver, see TestHelperResolver
+const envHelperResolver = "TULIP_TEST_HELPER_RESOLVER"
+
+// TestHelperResolver claims the PID file like 'dns serve' does, until it's told to stop
+func TestHelperResolver(t *testing.T) {
+	if os.Getenv(envHelperResolver) == "" {
+		t.Skip("only runs as a helper process")
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM)
+	release, err := Claim()
+	if err != nil {
+		os.Exit(1)
+	}
+	<-stop
+	release()
+	os.Exit(0)
+}
+
+// startHelperResolver runs the test binary as a resolver and waits until it claims the PID file
+func startHelperResolver(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperResolver$")
+	cmd.Env = append(os.Environ(), envHelperResolver+"=1")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	for deadline := time.Now().Add(5 * time.Second); !IsRunning(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("helper resolver didn't claim the PID file")
+		}
+	}
+	return cmd
+}
+
+// useStateDir keeps the PID file in a temporary directory
+func useStateDir(t *testing.T) {
+	t.Helper()
+	t.Setenv(config.EnvHome, t.TempDir())
+	if err := os.MkdirAll(config.GetStateDirPath(), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStop(t *testing.T) {
+	useStateDir(t)
+	cmd := startHelperResolver(t)
+
+	if pid, running := runningPid(); !running || pid != cmd.Process.Pid {
+		t.Fatalf("runningPid = %d, %v, want %d, true", pid, running, cmd.Process.Pid)
+	}
+	if stopped, err := Stop(); err != nil || !stopped {
+		t.Fatalf("Stop = %v, %v, want true, nil", stopped, err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("helper resolver exited with %v", err)
+	}
+	if _, err := os.Stat(getPidFilePath()); !os.IsNotExist(err) {
+		t.Errorf("PID file is still there: %v", err)
+	}
+}
+
+func TestStopStalePidFile(t *testing.T) {
+	useStateDir(t)
+
+	// The recorded process is alive, but isn't a resolver holding the PID file
+	if err := os.WriteFile(getPidFilePath(), []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if IsRunning() {
+		t.Error("IsRunning = true for a PID file nobody holds")
+	}
+	if stopped, err := Stop(); err != nil || stopped {
+		t.Errorf("Stop = %v, %v, want false, nil", stopped, err)
+	}
+	if _, err := os.Stat(getPidFilePath()); !os.IsNotExist(err) {
+		t.Errorf("stale PID file wasn't removed: %v", err)
+	}
+}
+
+func TestClaim(t *testing.T) {
+	useStateDir(t)
+
+	release, err := Claim()
+	if err != nil {
+		t.Fatalf("Claim returned %v", err)
+	}
+	if pid, running := runningPid(); !running || pid != os.Getpid() {
+		t.Errorf("runningPid = %d, %v, want %d, true", pid, running, os.Getpid())
+	}
+	if _, err := Claim(); !errors.Is(err, util.ErrPortInUse) {
+		t.Errorf("second Claim returned %v, want ErrPortInUse", err)
+	}
+
+	release()
+	if IsRunning() {
+		t.Error("IsRunning = true after releasing the claim")
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+// Package dns manages the lifecycle of Tulip's background DNS resolver
+package dns
+
+import "os"
+
+// lockFile is a no-op on platforms without advisory locks
+func lockFile(file *os.File) error {
+	return nil
+}
+
+// isLocked can't tell on platforms without advisory locks, so the recorded process ID is trusted
+func isLocked(file *os.File) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+// Package dns manages the lifecycle of Tulip's background DNS resolver
+package dns
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile locks a file for as long as it's open, or returns errLocked if another process holds it
+func lockFile(file *os.File) error {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return errLocked
+	}
+	return err
+}
+
+// isLocked checks if another process holds the lock of a file
+func isLocked(file *os.File) (bool, error) {
+	err := lockFile(file)
+	if errors.Is(err, errLocked) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	return false, syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
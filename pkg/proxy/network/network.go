@@ -2,10 +2,10 @@
 package network
 
 import (
-	"bytes"
-	"os/exec"
+	"errors"
 
 	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/runtime"
 	"github.com/pierrestoffe/tulip/pkg/util"
 )
 
@@ -19,23 +19,19 @@ func Start() (bool, error) {
 	}
 
 	// Check if the proxy network is already running
-	if IsRunning() {
+	running, err := IsRunning()
+	if err != nil {
+		return false, err
+	}
+	if running {
 		util.PrintWarning("Proxy network " + cfg.Docker.NetworkName + " is already running")
 		return false, nil
 	}
 
 	// Start the proxy network
 	util.PrintInfo("Starting " + cfg.Docker.NetworkName + " proxy network..")
-	cmd := exec.Command("docker", "network", "create", cfg.Docker.NetworkName)
-
-	// Capture stderr
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	// Run command and handle errors
-	if err := cmd.Run(); err != nil {
-		errMsg := stderr.String()
-		return false, util.HandleError("Error starting "+cfg.Docker.NetworkName+" proxy network", err, errMsg)
+	if err := runtime.Get().NetworkCreate(cfg.Docker.NetworkName); err != nil {
+		return false, util.HandleError("Error starting "+cfg.Docker.NetworkName+" proxy network", err)
 	}
 
 	util.PrintInfoReplace("Proxy network " + cfg.Docker.NetworkName + " started")
@@ -52,7 +48,11 @@ func Stop() (bool, error) {
 	}
 
 	// Check if the proxy network is running
-	if !IsRunning() {
+	running, err := IsRunning()
+	if err != nil {
+		return false, err
+	}
+	if !running {
 		util.PrintWarning("Proxy network " + cfg.Docker.NetworkName + " is already stopped.")
 		return false, nil
 	}
@@ -60,16 +60,8 @@ func Stop() (bool, error) {
 	util.PrintInfo("Stopping " + cfg.Docker.NetworkName + " network..")
 
 	// Stop the proxy network
-	cmd := exec.Command("docker", "network", "remove", cfg.Docker.NetworkName)
-
-	// Capture stderr
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	// Run command and handle errors
-	if err := cmd.Run(); err != nil {
-		errMsg := stderr.String()
-		return false, util.HandleError("Error stopping "+cfg.Docker.NetworkName+" proxy network", err, errMsg)
+	if err := runtime.Get().NetworkRemove(cfg.Docker.NetworkName); err != nil {
+		return false, util.HandleError("Error stopping "+cfg.Docker.NetworkName+" proxy network", err)
 	}
 
 	util.PrintInfoReplace("Proxy network " + cfg.Docker.NetworkName + " was stopped")
@@ -79,26 +71,28 @@ func Stop() (bool, error) {
 // Ensure verifies that the proxy network exists and creates it if missing
 // Returns an error if the network cannot be created
 func Ensure() error {
-	if IsRunning() {
-		return nil
+	running, err := IsRunning()
+	if err != nil || running {
+		return err
 	}
-	_, err := Start()
+	_, err = Start()
 	return err
 }
 
-// IsRunning checks if the proxy network exists in Docker
-// Returns true if the network exists and is operational
-func IsRunning() bool {
+// IsRunning checks if the proxy network exists in the container runtime
+// Returns true if the network exists, and an error if the runtime couldn't tell
+func IsRunning() (bool, error) {
 	// Get configuration
 	cfg, err := config.Get()
 	if err != nil {
-		util.HandleError("Failed to load configuration", err)
-		return false
+		return false, util.HandleError("Failed to load configuration", err)
 	}
 
-	cmd := exec.Command("docker", "network", "inspect", cfg.Docker.NetworkName)
-	if err := cmd.Run(); err != nil {
-		return false
+	if _, err := runtime.Get().NetworkInspect(cfg.Docker.NetworkName); err != nil {
+		if errors.Is(err, runtime.ErrNotFound) {
+			return false, nil
+		}
+		return false, util.HandleError("Failed to check if proxy network "+cfg.Docker.NetworkName+" is running", err)
 	}
-	return true
+	return true, nil
 }
@@ -0,0 +1,98 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/runtime"
+	"github.com/pierrestoffe/tulip/pkg/runtime/runtimetest"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// useFake runs the test against an in-memory runtime and the default configuration
+func useFake(t *testing.T) (*runtimetest.Fake, *config.Config) {
+	t.Helper()
+	t.Setenv(config.EnvHome, t.TempDir())
+	cfg := config.DefaultConfig()
+	config.Set(cfg)
+	fake := runtimetest.NewFake()
+	runtime.Set(fake)
+	return fake, cfg
+}
+
+// isRunning checks if the network exists, failing the test if the runtime couldn't tell
+func isRunning(t *testing.T) bool {
+	t.Helper()
+	running, err := IsRunning()
+	if err != nil {
+		t.Fatalf("IsRunning returned %v", err)
+	}
+	return running
+}
+
+func TestStartStop(t *testing.T) {
+	fake, cfg := useFake(t)
+
+	if isRunning(t) {
+		t.Fatal("network is running before Start")
+	}
+	if started, err := Start(); err != nil || !started {
+		t.Fatalf("Start = %v, %v, want true, nil", started, err)
+	}
+	if !isRunning(t) {
+		t.Error("network isn't running after Start")
+	}
+	if started, err := Start(); err != nil || started {
+		t.Errorf("second Start = %v, %v, want false, nil", started, err)
+	}
+
+	if stopped, err := Stop(); err != nil || !stopped {
+		t.Fatalf("Stop = %v, %v, want true, nil", stopped, err)
+	}
+	if isRunning(t) {
+		t.Error("network is running after Stop")
+	}
+	if stopped, err := Stop(); err != nil || stopped {
+		t.Errorf("second Stop = %v, %v, want false, nil", stopped, err)
+	}
+
+	want := []string{"NetworkCreate " + cfg.Docker.NetworkName, "NetworkRemove " + cfg.Docker.NetworkName}
+	if !slices.Equal(fake.Calls, want) {
+		t.Errorf("calls = %v, want %v", fake.Calls, want)
+	}
+}
+
+func TestEnsure(t *testing.T) {
+	fake, cfg := useFake(t)
+
+	for range 2 {
+		if err := Ensure(); err != nil {
+			t.Fatalf("Ensure returned %v", err)
+		}
+	}
+	want := []string{"NetworkCreate " + cfg.Docker.NetworkName}
+	if !slices.Equal(fake.Calls, want) {
+		t.Errorf("calls = %v, want %v", fake.Calls, want)
+	}
+}
+
+func TestRuntimeUnavailable(t *testing.T) {
+	fake, _ := useFake(t)
+	fake.Err = fmt.Errorf("%w: connection refused", util.ErrRuntimeUnavailable)
+
+	if running, err := IsRunning(); !errors.Is(err, util.ErrRuntimeUnavailable) || running {
+		t.Errorf("IsRunning = %v, %v, want false, ErrRuntimeUnavailable", running, err)
+	}
+	if _, err := Start(); !errors.Is(err, util.ErrRuntimeUnavailable) {
+		t.Errorf("Start returned %v, want ErrRuntimeUnavailable", err)
+	}
+	if _, err := Stop(); !errors.Is(err, util.ErrRuntimeUnavailable) {
+		t.Errorf("Stop returned %v, want ErrRuntimeUnavailable", err)
+	}
+	if len(fake.Calls) > 0 {
+		t.Errorf("calls = %v, want none", fake.Calls)
+	}
+}
@@ -4,13 +4,21 @@ package proxy
 import (
 	"github.com/pierrestoffe/tulip/pkg/config"
 	"github.com/pierrestoffe/tulip/pkg/proxy/container"
+	"github.com/pierrestoffe/tulip/pkg/proxy/dns"
 	"github.com/pierrestoffe/tulip/pkg/proxy/network"
+	proxySetup "github.com/pierrestoffe/tulip/pkg/setup/proxy"
 	"github.com/pierrestoffe/tulip/pkg/util"
 )
 
-// Start initializes and launches both the proxy network and container
-// Returns an error if either component fails to start
+// Start initializes and launches the proxy network, container and DNS resolver
+// Returns an error if any component fails to start
 func Start() error {
+	// Report outdated proxy files before creating anything
+	// They're generated by init and render, which may need to ask about the changes made by hand
+	if err := proxySetup.Check(); err != nil {
+		return err
+	}
+
 	successNetwork, err := network.Start()
 	if err != nil {
 		return err
@@ -19,8 +27,12 @@ func Start() error {
 	if err != nil {
 		return err
 	}
+	successDNS, err := dns.Start()
+	if err != nil {
+		return err
+	}
 
-	if successNetwork || successContainer {
+	if successNetwork || successContainer || successDNS {
 		util.PrintSuccess("Tulip's proxy was successfully started!")
 	}
 
@@ -33,9 +45,13 @@ func Start() error {
 	return nil
 }
 
-// Stop terminates both the proxy container and network
-// Returns an error if either component fails to stop
+// Stop terminates the DNS resolver, proxy container and network
+// Returns an error if any component fails to stop
 func Stop() error {
+	successDNS, err := dns.Stop()
+	if err != nil {
+		return err
+	}
 	successContainer, err := container.Stop()
 	if err != nil {
 		return err
@@ -45,7 +61,7 @@ func Stop() error {
 		return err
 	}
 
-	if successNetwork || successContainer {
+	if successNetwork || successContainer || successDNS {
 		util.PrintSuccess("Tulip's proxy was successfully stopped")
 	}
 	return nil
@@ -60,7 +76,7 @@ func Restart() error {
 	return Start()
 }
 
-// Ensure verifies that both the network and container are running
+// Ensure verifies that the network, container and DNS resolver are running
 // Starts them if they are not already running
 func Ensure() error {
 	if err := network.Ensure(); err != nil {
@@ -69,5 +85,8 @@ func Ensure() error {
 	if err := container.Ensure(); err != nil {
 		return err
 	}
+	if err := dns.Ensure(); err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,228 @@
+// Package runtime provides the implementation of the container runtime backed by the docker CLI
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// DockerCLI drives a container runtime through its Docker-compatible command-line client
+// It works with docker, podman and nerdctl, which all accept the same subcommands
+type DockerCLI struct {
+	Command string // Name or path of the runtime executable
+}
+
+// NewDockerCLI creates a runtime using the docker executable found in PATH
+func NewDockerCLI() *DockerCLI {
+	return &DockerCLI{Command: "docker"}
+}
+
+// NetworkCreate creates a network with the given name
+func (d *DockerCLI) NetworkCreate(name string) error {
+	_, err := d.run("network", "create", name)
+	return err
+}
+
+// NetworkRemove removes the network with the given name
+func (d *DockerCLI) NetworkRemove(name string) error {
+	_, err := d.run("network", "remove", name)
+	return err
+}
+
+// NetworkInspect returns the network with the given name, or ErrNotFound
+func (d *DockerCLI) NetworkInspect(name string) (*Network, error) {
+	// Networks are matched here since name filters differ between runtimes
+	output, err := d.run("network", "ls", "--format", "{{json .}}")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		// Field names are matched case-insensitively, which covers every runtime
+		var entry struct {
+			ID     string `json:"ID"`
+			Name   string `json:"Name"`
+			Driver string `json:"Driver"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, err
+		}
+		if entry.Name == name {
+			return &Network{ID: entry.ID, Name: entry.Name, Driver: entry.Driver}, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// ComposeUp creates and starts the services of a Compose project in the background
+func (d *DockerCLI) ComposeUp(project ComposeProject) error {
+	cmdArgs := []string{"up", "-d"}
+	if project.RemoveOrphans {
+		cmdArgs = append(cmdArgs, "--remove-orphans")
+	}
+	return d.compose(project, cmdArgs...)
+}
+
+// ComposeDown stops and removes the services of a Compose project, and optionally its volumes
+func (d *DockerCLI) ComposeDown(project ComposeProject, removeVolumes bool) error {
+	cmdArgs := []string{"down", "--remove-orphans"}
+	if removeVolumes {
+		cmdArgs = append(cmdArgs, "--volumes")
+	}
+	return d.compose(project, cmdArgs...)
+}
+
+// ComposeStop stops the services of a Compose project without removing them
+func (d *DockerCLI) ComposeStop(project ComposeProject) error {
+	return d.compose(project, "stop")
+}
+
+// ComposePause suspends the services of a Compose project
+func (d *DockerCLI) ComposePause(project ComposeProject) error {
+	return d.compose(project, "pause")
+}
+
+// ComposeUnpause resumes the services of a Compose project
+func (d *DockerCLI) ComposeUnpause(project ComposeProject) error {
+	return d.compose(project, "unpause")
+}
+
+// ContainerList returns the containers matching the filter
+func (d *DockerCLI) ContainerList(filter ContainerFilter) ([]Container, error) {
+	cmdArgs := []string{"ps", "--no-trunc", "--format", "{{json .}}"}
+	if filter.All || (filter.State != "" && filter.State != StateRunning && filter.State != StatePaused) {
+		cmdArgs = append(cmdArgs, "--all")
+	}
+	if filter.Name != "" {
+		cmdArgs = append(cmdArgs, "--filter", "name="+filter.Name)
+	}
+	if filter.State != "" {
+		cmdArgs = append(cmdArgs, "--filter", "status="+filter.State)
+	}
+	labelKeys := make([]string, 0, len(filter.Labels))
+	for key := range filter.Labels {
+		labelKeys = append(labelKeys, key)
+	}
+	sort.Strings(labelKeys)
+	for _, key := range labelKeys {
+		cmdArgs = append(cmdArgs, "--filter", "label="+key+"="+filter.Labels[key])
+	}
+
+	output, err := d.run(cmdArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	containers := make([]Container, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		// Podman prints names and labels as JSON values, docker and nerdctl as strings
+		var entry struct {
+			ID     string          `json:"ID"`
+			Names  json.RawMessage `json:"Names"`
+			Image  string          `json:"Image"`
+			State  string          `json:"State"`
+			Labels json.RawMessage `json:"Labels"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, err
+		}
+		containers = append(containers, Container{
+			ID:     entry.ID,
+			Name:   parseNames(entry.Names),
+			Image:  entry.Image,
+			State:  strings.ToLower(entry.State),
+			Labels: parseLabels(entry.Labels),
+		})
+	}
+	return containers, nil
+}
+
+// compose runs a docker compose subcommand against a Compose project
+func (d *DockerCLI) compose(project ComposeProject, cmdArgs ...string) error {
+	cmd := exec.Command(d.Command, append([]string{"compose"}, cmdArgs...)...)
+	cmd.Dir = project.Dir
+	cmd.Env = os.Environ()
+	for key, value := range project.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	return runCmd(cmd)
+}
+
+// run executes a docker subcommand and returns its standard output
+func (d *DockerCLI) run(cmdArgs ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd := exec.Command(d.Command, cmdArgs...)
+	cmd.Stdout = &stdout
+	if err := runCmd(cmd); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// runCmd runs a command and includes its error output in the returned error
+func runCmd(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	util.PrintDebug("Running " + strings.Join(cmd.Args, " "))
+	if err := cmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+
+		// Missing client or unreachable daemon
+		if errors.Is(err, exec.ErrNotFound) || strings.Contains(errMsg, "Cannot connect to") {
+			err = fmt.Errorf("%w: %w", util.ErrRuntimeUnavailable, err)
+		}
+		if errMsg != "" {
+			return fmt.Errorf("%w\n%s", err, errMsg)
+		}
+		return err
+	}
+	return nil
+}
+
+// parseNames returns the first name of a container, printed either as a string or a list
+func parseNames(names json.RawMessage) string {
+	var list []string
+	if err := json.Unmarshal(names, &list); err == nil {
+		if len(list) == 0 {
+			return ""
+		}
+		return list[0]
+	}
+
+	var name string
+	json.Unmarshal(names, &name)
+	name, _, _ = strings.Cut(name, ",")
+	return name
+}
+
+// parseLabels decodes container labels, printed either as an object or a comma-separated string
+func parseLabels(labels json.RawMessage) map[string]string {
+	parsed := make(map[string]string)
+	if err := json.Unmarshal(labels, &parsed); err == nil {
+		return parsed
+	}
+
+	var list string
+	json.Unmarshal(labels, &list)
+	for _, label := range strings.Split(list, ",") {
+		if key, value, found := strings.Cut(label, "="); found {
+			parsed[key] = value
+		}
+	}
+	return parsed
+}
@@ -0,0 +1,243 @@
+// Package runtime provides a client for the Docker Engine API over a unix socket
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// Timeout applied to Engine API requests that don't stream
+const engineTimeout = 10 * time.Second
+
+// Engine talks to the Docker Engine API over a unix socket
+// Compose commands are delegated to the docker CLI, which the Engine API can't replace
+type Engine struct {
+	*DockerCLI
+	Socket string // Path to the unix socket of the Engine API
+	client *http.Client
+}
+
+// NewEngine creates a runtime using the Engine API listening on the given unix socket
+func NewEngine(socket string, cli *DockerCLI) *Engine {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network string, addr string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}
+	return &Engine{
+		DockerCLI: cli,
+		Socket:    socket,
+		client:    &http.Client{Transport: transport},
+	}
+}
+
+// Ping checks that the Engine API answers on the socket
+func (e *Engine) Ping() error {
+	response, err := e.request(http.MethodGet, "/_ping", nil, nil)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
+// NetworkCreate creates a network with the given name
+func (e *Engine) NetworkCreate(name string) error {
+	body := map[string]any{"Name": name, "CheckDuplicate": true}
+	response, err := e.request(http.MethodPost, "/networks/create", nil, body)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
+// NetworkRemove removes the network with the given name
+func (e *Engine) NetworkRemove(name string) error {
+	response, err := e.request(http.MethodDelete, "/networks/"+name, nil, nil)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
+// NetworkInspect returns the network with the given name, or ErrNotFound
+func (e *Engine) NetworkInspect(name string) (*Network, error) {
+	response, err := e.request(http.MethodGet, "/networks/"+name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var entry struct {
+		ID     string `json:"Id"`
+		Name   string `json:"Name"`
+		Driver string `json:"Driver"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&entry); err != nil {
+		return nil, err
+	}
+
+	// The Engine API also resolves ID prefixes, only exact names are wanted
+	if entry.Name != name {
+		return nil, ErrNotFound
+	}
+	return &Network{ID: entry.ID, Name: entry.Name, Driver: entry.Driver}, nil
+}
+
+// ContainerList returns the containers matching the filter
+func (e *Engine) ContainerList(filter ContainerFilter) ([]Container, error) {
+	filters := make(map[string][]string)
+	if filter.Name != "" {
+		filters["name"] = []string{filter.Name}
+	}
+	if filter.State != "" {
+		filters["status"] = []string{filter.State}
+	}
+	for key, value := range filter.Labels {
+		filters["label"] = append(filters["label"], key+"="+value)
+	}
+
+	query := url.Values{}
+	if filter.All || (filter.State != "" && filter.State != StateRunning && filter.State != StatePaused) {
+		query.Set("all", "1")
+	}
+	if len(filters) > 0 {
+		encoded, err := json.Marshal(filters)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("filters", string(encoded))
+	}
+
+	response, err := e.request(http.MethodGet, "/containers/json", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var entries []struct {
+		ID     string            `json:"Id"`
+		Names  []string          `json:"Names"`
+		Image  string            `json:"Image"`
+		State  string            `json:"State"`
+		Labels map[string]string `json:"Labels"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	containers := make([]Container, 0, len(entries))
+	for _, entry := range entries {
+		name := ""
+		if len(entry.Names) > 0 {
+			name = strings.TrimPrefix(entry.Names[0], "/")
+		}
+		containers = append(containers, Container{
+			ID:     entry.ID,
+			Name:   name,
+			Image:  entry.Image,
+			State:  entry.State,
+			Labels: entry.Labels,
+		})
+	}
+	return containers, nil
+}
+
+// request sends a request to the Engine API and returns the response if it succeeded
+func (e *Engine) request(method string, path string, query url.Values, body any) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), engineTimeout)
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, engineURL(path, query), reader)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := e.do(request)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// Release the context once the body has been read
+	response.Body = &cancelReadCloser{ReadCloser: response.Body, cancel: cancel}
+	return response, nil
+}
+
+// do sends a request and turns unsuccessful responses into errors
+func (e *Engine) do(request *http.Request) (*http.Response, error) {
+	util.PrintDebug("Requesting " + request.Method + " " + request.URL.RequestURI() + " from " + e.Socket)
+	response, err := e.client.Do(request)
+	if err != nil {
+		// Requests cancelled by the caller aren't a sign of an unreachable daemon
+		if request.Context().Err() != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", util.ErrRuntimeUnavailable, err)
+	}
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return response, nil
+	}
+	defer response.Body.Close()
+
+	var apiError struct {
+		Message string `json:"message"`
+	}
+	json.NewDecoder(response.Body).Decode(&apiError)
+	if apiError.Message == "" {
+		apiError.Message = response.Status
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", apiError.Message, ErrNotFound)
+	}
+	return nil, fmt.Errorf("engine API error: %s", apiError.Message)
+}
+
+// engineURL builds the URL of an Engine API endpoint
+// The host is ignored since requests are sent over the unix socket
+func engineURL(path string, query url.Values) string {
+	u := url.URL{Scheme: "http", Host: "docker", Path: path}
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// cancelReadCloser cancels the request context once the response body is closed
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the response body and releases the request context
+func (c *cancelReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,157 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// newEngineServer serves the handler on a temporary unix socket and returns an Engine talking to it
+func newEngineServer(t *testing.T, handler http.Handler) *Engine {
+	t.Helper()
+	// Socket paths are limited to about a hundred bytes, which t.TempDir() may exceed
+	dir, err := os.MkdirTemp("", "engine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "docker.sock")
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return NewEngine(socket, NewDockerCLI())
+}
+
+// writeJSON answers a request with a JSON body
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// decodeFilters returns the filters sent in the query of a request
+func decodeFilters(t *testing.T, r *http.Request) map[string][]string {
+	t.Helper()
+	filters := make(map[string][]string)
+	if encoded := r.URL.Query().Get("filters"); encoded != "" {
+		if err := json.Unmarshal([]byte(encoded), &filters); err != nil {
+			t.Errorf("invalid filters %q: %v", encoded, err)
+		}
+	}
+	return filters
+}
+
+func TestEnginePing(t *testing.T) {
+	engine := newEngineServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_ping" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		fmt.Fprint(w, "OK")
+	}))
+	if err := engine.Ping(); err != nil {
+		t.Errorf("Ping returned %v", err)
+	}
+}
+
+func TestEnginePingUnavailable(t *testing.T) {
+	engine := NewEngine(filepath.Join(t.TempDir(), "missing.sock"), NewDockerCLI())
+	if err := engine.Ping(); !errors.Is(err, util.ErrRuntimeUnavailable) {
+		t.Errorf("Ping returned %v, want ErrRuntimeUnavailable", err)
+	}
+}
+
+func TestEngineNetworkInspect(t *testing.T) {
+	engine := newEngineServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/networks/tulip", "/networks/tul":
+			// The Engine API resolves name prefixes to the same network
+			writeJSON(w, http.StatusOK, map[string]string{"Id": "abc123", "Name": "tulip", "Driver": "bridge"})
+		default:
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "network missing not found"})
+		}
+	}))
+
+	network, err := engine.NetworkInspect("tulip")
+	if err != nil {
+		t.Fatalf("NetworkInspect returned %v", err)
+	}
+	if *network != (Network{ID: "abc123", Name: "tulip", Driver: "bridge"}) {
+		t.Errorf("NetworkInspect = %+v", *network)
+	}
+
+	for _, name := range []string{"tul", "missing"} {
+		if _, err := engine.NetworkInspect(name); !errors.Is(err, ErrNotFound) {
+			t.Errorf("NetworkInspect(%q) returned %v, want ErrNotFound", name, err)
+		}
+	}
+}
+
+func TestEngineNetworkCreateError(t *testing.T) {
+	engine := newEngineServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.Method != http.MethodPost || r.URL.Path != "/networks/create" || body["Name"] != "tulip" {
+			t.Errorf("unexpected request %s %s %v", r.Method, r.URL, body)
+		}
+		writeJSON(w, http.StatusConflict, map[string]string{"message": "network with name tulip already exists"})
+	}))
+
+	err := engine.NetworkCreate("tulip")
+	if err == nil || err.Error() != "engine API error: network with name tulip already exists" {
+		t.Errorf("NetworkCreate returned %v", err)
+	}
+}
+
+func TestEngineContainerList(t *testing.T) {
+	engine := newEngineServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/containers/json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		filters := decodeFilters(t, r)
+		want := map[string][]string{"label": {"com.docker.compose.project=demo"}, "status": {"exited"}}
+		if len(filters) != len(want) || !slices.Equal(filters["label"], want["label"]) || !slices.Equal(filters["status"], want["status"]) {
+			t.Errorf("filters = %v, want %v", filters, want)
+		}
+		if r.URL.Query().Get("all") != "1" {
+			t.Errorf("all isn't set for exited containers")
+		}
+		writeJSON(w, http.StatusOK, []map[string]any{{
+			"Id":     "def456",
+			"Names":  []string{"/demo-web-1"},
+			"Image":  "nginx:alpine",
+			"State":  "exited",
+			"Labels": map[string]string{"com.docker.compose.project": "demo"},
+		}})
+	}))
+
+	containers, err := engine.ContainerList(ContainerFilter{
+		Labels: map[string]string{LabelComposeProject: "demo"},
+		State:  StateExited,
+	})
+	if err != nil {
+		t.Fatalf("ContainerList returned %v", err)
+	}
+	if len(containers) != 1 {
+		t.Fatalf("ContainerList returned %d containers, want 1", len(containers))
+	}
+	got := containers[0]
+	if got.ID != "def456" || got.Name != "demo-web-1" || got.Image != "nginx:alpine" || got.State != StateExited ||
+		got.Labels[LabelComposeProject] != "demo" {
+		t.Errorf("ContainerList = %+v", got)
+	}
+}
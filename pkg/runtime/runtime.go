@@ -0,0 +1,134 @@
+// Package runtime abstracts the container runtime used by Tulip to manage networks and containers
+package runtime
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+)
+
+// ErrNotFound is returned when the requested network or container doesn't exist
+var ErrNotFound = errors.New("not found")
+
+// Runtime is implemented by every container runtime Tulip can drive
+type Runtime interface {
+	// NetworkCreate creates a network with the given name
+	NetworkCreate(name string) error
+	// NetworkRemove removes the network with the given name
+	NetworkRemove(name string) error
+	// NetworkInspect returns the network with the given name, or ErrNotFound
+	NetworkInspect(name string) (*Network, error)
+
+	// ComposeUp creates and starts the services of a Compose project in the background
+	ComposeUp(project ComposeProject) error
+	// ComposeDown stops and removes the services of a Compose project, and optionally its volumes
+	ComposeDown(project ComposeProject, removeVolumes bool) error
+	// ComposeStop stops the services of a Compose project without removing them
+	ComposeStop(project ComposeProject) error
+	// ComposePause suspends the services of a Compose project
+	ComposePause(project ComposeProject) error
+	// ComposeUnpause resumes the services of a Compose project
+	ComposeUnpause(project ComposeProject) error
+
+	// ContainerList returns the containers matching the filter
+	ContainerList(filter ContainerFilter) ([]Container, error)
+}
+
+// Network describes a container network
+type Network struct {
+	ID     string
+	Name   string
+	Driver string
+}
+
+// Container describes a container known to the runtime
+type Container struct {
+	ID     string
+	Name   string
+	Image  string
+	State  string // One of the State* constants
+	Labels map[string]string
+}
+
+// Container states reported by the runtime
+const (
+	StateCreated = "created"
+	StateRunning = "running"
+	StatePaused  = "paused"
+	StateExited  = "exited"
+)
+
+// LabelComposeProject is the label holding the Compose project a container belongs to
+const LabelComposeProject = "com.docker.compose.project"
+
+// ContainerFilter restricts the containers returned by ContainerList
+type ContainerFilter struct {
+	Name   string            // Only containers whose name contains this value
+	Labels map[string]string // Only containers carrying all of these labels
+	State  string            // Only containers in this state
+	All    bool              // Include stopped containers
+}
+
+// ComposeProject identifies a Compose project on disk
+type ComposeProject struct {
+	Dir           string            // Directory holding the docker-compose.yml file
+	Env           map[string]string // Variables substituted in the Compose file
+	RemoveOrphans bool              // Remove containers of services no longer in the Compose file
+}
+
+var (
+	// Runtime instance shared by the application
+	current      Runtime
+	currentMutex sync.RWMutex
+)
+
+// Get returns the container runtime used by Tulip
+func Get() Runtime {
+	currentMutex.RLock()
+	if current != nil {
+		defer currentMutex.RUnlock()
+		return current
+	}
+	currentMutex.RUnlock()
+
+	currentMutex.Lock()
+	defer currentMutex.Unlock()
+	if current == nil {
+		current = newDefault()
+	}
+	return current
+}
+
+// Set replaces the container runtime used by Tulip, e.g. with a runtimetest.Fake in tests
+func Set(r Runtime) {
+	currentMutex.Lock()
+	defer currentMutex.Unlock()
+	current = r
+}
+
+// newDefault creates the runtime matching the configuration
+// The Engine API is used when its socket is reachable, the runtime's CLI otherwise
+func newDefault() Runtime {
+	cfg, err := config.Get()
+	if err != nil {
+		return NewDockerCLI()
+	}
+	cli := &DockerCLI{Command: cfg.Docker.Runtime}
+
+	// nerdctl doesn't expose a Docker-compatible API
+	if cfg.Docker.Runtime == config.RuntimeNerdctl {
+		return cli
+	}
+	socket := cfg.Docker.Socket()
+	if _, err := os.Stat(socket); err != nil {
+		return cli
+	}
+
+	engine := NewEngine(socket, cli)
+	if err := engine.Ping(); err != nil {
+		return cli
+	}
+	return engine
+}
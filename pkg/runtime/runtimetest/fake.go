@@ -0,0 +1,283 @@
+// Package runtimetest provides an in-memory container runtime for tests
+package runtimetest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pierrestoffe/tulip/pkg/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+// Fake is an in-memory runtime that records the state a real runtime would have
+// Compose projects are read from their docker-compose.yml to create one container per service
+type Fake struct {
+	mutex      sync.Mutex
+	networks   map[string]*runtime.Network
+	containers map[string]*runtime.Container
+	nextID     int
+
+	Calls []string // Operations performed, e.g. "ComposeUp /path"
+	Err   error    // Returned by every operation when set, e.g. to simulate a daemon that isn't running
+}
+
+// NewFake creates an empty in-memory runtime
+func NewFake() *Fake {
+	return &Fake{
+		networks:   make(map[string]*runtime.Network),
+		containers: make(map[string]*runtime.Container),
+	}
+}
+
+// NetworkCreate creates a network with the given name
+func (f *Fake) NetworkCreate(name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.record("NetworkCreate " + name)
+	if f.Err != nil {
+		return f.Err
+	}
+
+	if _, exists := f.networks[name]; exists {
+		return fmt.Errorf("network with name %s already exists", name)
+	}
+	f.networks[name] = &runtime.Network{ID: f.newID(), Name: name, Driver: "bridge"}
+	return nil
+}
+
+// NetworkRemove removes the network with the given name
+func (f *Fake) NetworkRemove(name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.record("NetworkRemove " + name)
+	if f.Err != nil {
+		return f.Err
+	}
+
+	if _, exists := f.networks[name]; !exists {
+		return fmt.Errorf("network %s: %w", name, runtime.ErrNotFound)
+	}
+	delete(f.networks, name)
+	return nil
+}
+
+// NetworkInspect returns the network with the given name, or runtime.ErrNotFound
+func (f *Fake) NetworkInspect(name string) (*runtime.Network, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	network, exists := f.networks[name]
+	if !exists {
+		return nil, runtime.ErrNotFound
+	}
+	copied := *network
+	return &copied, nil
+}
+
+// ComposeUp creates and starts one container per service of the Compose project
+func (f *Fake) ComposeUp(project runtime.ComposeProject) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.record("ComposeUp " + project.Dir)
+	if f.Err != nil {
+		return f.Err
+	}
+
+	name, services, err := readCompose(project)
+	if err != nil {
+		return err
+	}
+
+	for serviceName, service := range services {
+		containerName := service.ContainerName
+		if containerName == "" {
+			containerName = name + "-" + serviceName + "-1"
+		}
+		labels := map[string]string{runtime.LabelComposeProject: name}
+		for key, value := range service.Labels {
+			labels[key] = value
+		}
+
+		if existing, exists := f.containers[containerName]; exists {
+			existing.State = runtime.StateRunning
+			continue
+		}
+		f.containers[containerName] = &runtime.Container{
+			ID:     f.newID(),
+			Name:   containerName,
+			Image:  service.Image,
+			State:  runtime.StateRunning,
+			Labels: labels,
+		}
+	}
+	return nil
+}
+
+// ComposeDown removes the containers of the Compose project
+func (f *Fake) ComposeDown(project runtime.ComposeProject, removeVolumes bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.record(fmt.Sprintf("ComposeDown %s volumes=%t", project.Dir, removeVolumes))
+	if f.Err != nil {
+		return f.Err
+	}
+
+	return f.eachProjectContainer(project, func(container *runtime.Container) {
+		delete(f.containers, container.Name)
+	})
+}
+
+// ComposeStop stops the containers of the Compose project
+func (f *Fake) ComposeStop(project runtime.ComposeProject) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.record("ComposeStop " + project.Dir)
+	if f.Err != nil {
+		return f.Err
+	}
+
+	return f.eachProjectContainer(project, func(container *runtime.Container) {
+		container.State = runtime.StateExited
+	})
+}
+
+// ComposePause pauses the running containers of the Compose project
+func (f *Fake) ComposePause(project runtime.ComposeProject) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.record("ComposePause " + project.Dir)
+	if f.Err != nil {
+		return f.Err
+	}
+
+	return f.eachProjectContainer(project, func(container *runtime.Container) {
+		if container.State == runtime.StateRunning {
+			container.State = runtime.StatePaused
+		}
+	})
+}
+
+// ComposeUnpause resumes the paused containers of the Compose project
+func (f *Fake) ComposeUnpause(project runtime.ComposeProject) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.record("ComposeUnpause " + project.Dir)
+	if f.Err != nil {
+		return f.Err
+	}
+
+	return f.eachProjectContainer(project, func(container *runtime.Container) {
+		if container.State == runtime.StatePaused {
+			container.State = runtime.StateRunning
+		}
+	})
+}
+
+// ContainerList returns the containers matching the filter, sorted by name
+func (f *Fake) ContainerList(filter runtime.ContainerFilter) ([]runtime.Container, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	containers := make([]runtime.Container, 0)
+	for _, container := range f.containers {
+		if !matches(container, filter) {
+			continue
+		}
+		copied := *container
+		containers = append(containers, copied)
+	}
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].Name < containers[j].Name
+	})
+	return containers, nil
+}
+
+// eachProjectContainer applies a change to every container of a Compose project
+func (f *Fake) eachProjectContainer(project runtime.ComposeProject, change func(container *runtime.Container)) error {
+	name, _, err := readCompose(project)
+	if err != nil {
+		return err
+	}
+	for _, container := range f.containers {
+		if container.Labels[runtime.LabelComposeProject] == name {
+			change(container)
+		}
+	}
+	return nil
+}
+
+// record appends an operation to the list of calls
+func (f *Fake) record(call string) {
+	f.Calls = append(f.Calls, call)
+}
+
+// newID generates a unique identifier
+func (f *Fake) newID() string {
+	f.nextID++
+	return fmt.Sprintf("fake%012d", f.nextID)
+}
+
+// fakeService is the subset of a Compose service read by the fake runtime
+type fakeService struct {
+	Image         string            `yaml:"image"`
+	ContainerName string            `yaml:"container_name"`
+	Labels        map[string]string `yaml:"labels"`
+}
+
+// readCompose reads the project name and services of a Compose project
+// Variables of the project environment are substituted in the file
+func readCompose(project runtime.ComposeProject) (string, map[string]fakeService, error) {
+	content, err := os.ReadFile(filepath.Join(project.Dir, "docker-compose.yml"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	expanded := os.Expand(string(content), func(key string) string {
+		key, fallback, _ := strings.Cut(key, ":-")
+		if value, exists := project.Env[key]; exists {
+			return value
+		}
+		return fallback
+	})
+
+	var compose struct {
+		Name     string                 `yaml:"name"`
+		Services map[string]fakeService `yaml:"services"`
+	}
+	if err := yaml.Unmarshal([]byte(expanded), &compose); err != nil {
+		return "", nil, err
+	}
+	if compose.Name == "" {
+		compose.Name = filepath.Base(project.Dir)
+	}
+	return compose.Name, compose.Services, nil
+}
+
+// matches checks if a container satisfies a filter
+func matches(container *runtime.Container, filter runtime.ContainerFilter) bool {
+	if filter.Name != "" && !strings.Contains(container.Name, filter.Name) {
+		return false
+	}
+	if filter.State != "" && container.State != filter.State {
+		return false
+	}
+	if filter.State == "" && !filter.All && container.State != runtime.StateRunning && container.State != runtime.StatePaused {
+		return false
+	}
+	for key, value := range filter.Labels {
+		if container.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
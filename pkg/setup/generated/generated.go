@@ -0,0 +1,266 @@
+// Package generated keeps track of the files generated by Tulip, so that the changes made to them
+// by hand survive a reinitialization
+package generated
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Ways to handle a generated file that was changed by hand
+const (
+	ResolutionAsk       = ""          // Ask the user, or keep the file if they can't answer
+	ResolutionKeep      = "keep"      // Keep the file as it is
+	ResolutionOverwrite = "overwrite" // Replace the file with its new content
+	ResolutionMerge     = "merge"     // Merge the changes made by hand into the new content
+)
+
+// Resolutions lists the ways to handle a generated file that was changed by hand
+var Resolutions = []string{ResolutionKeep, ResolutionOverwrite, ResolutionMerge}
+
+// Statuses of a generated file
+const (
+	StatusUnchanged = "unchanged" // File is as Tulip generated it
+	StatusModified  = "modified"  // File was changed by hand
+	StatusOutdated  = "outdated"  // File would be generated differently now, e.g. after an upgrade
+	StatusMissing   = "missing"   // File doesn't exist
+	StatusUntracked = "untracked" // File wasn't recorded when generated, e.g. by an older version of Tulip
+)
+
+// File is the content Tulip generates for a file
+type File struct {
+	Path    string      // Full path of the file
+	Content []byte      // Generated content
+	Mode    os.FileMode // Permissions of the file, util.ModePublic if zero
+}
+
+// Drift tells how a file differs from what Tulip generated
+type Drift struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+// checksums lists the checksums of the generated files, by path relative to Tulip's directory
+type checksums struct {
+	Files map[string]string `yaml:"files"`
+}
+
+// Way to handle the generated files changed by hand during this run
+var resolution = ResolutionAsk
+
+// SetResolution selects how to handle the generated files that were changed by hand
+func SetResolution(value string) {
+	resolution = value
+}
+
+// Write creates or updates a generated file, preserving the changes made to it by hand
+// The generated content is recorded so that later changes to the file can be detected and merged
+func Write(file File) error {
+	relPath, err := relativePath(file.Path)
+	if err != nil {
+		return err
+	}
+	sums, err := readChecksums()
+	if err != nil {
+		return err
+	}
+
+	content := file.Content
+	backup := false
+	current, err := os.ReadFile(file.Path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return util.HandleError("Failed to read "+file.Path, err)
+	case bytes.Equal(current, file.Content):
+	case sums.Files[relPath] == checksum(current):
+		// The file is as generated last time, so it can be replaced
+	default:
+		resolved, keep, err := resolve(file, relPath, current, sums.Files[relPath] != "")
+		if err != nil {
+			return err
+		}
+		if keep {
+			return nil
+		}
+		// Keep the version changed by hand in case the new content doesn't suit the user
+		content, backup = resolved, true
+	}
+
+	if err := util.CreateFile(file.Path, content, util.FileOptions{Mode: file.Mode, Backup: backup}); err != nil {
+		return err
+	}
+
+	// Record the generated content, which is the base of the next merge
+	basePath := filepath.Join(config.GetGeneratedDirPath(), relPath)
+	if err := os.MkdirAll(filepath.Dir(basePath), 0755); err != nil {
+		return util.HandleError("Failed to create directory "+filepath.Dir(basePath), err)
+	}
+	if err := util.WriteFileAtomic(basePath, file.Content, cmp.Or(file.Mode, util.ModePublic)); err != nil {
+		return err
+	}
+	sums.Files[relPath] = checksum(file.Content)
+	return writeChecksums(sums)
+}
+
+// Check compares generated files with what Tulip generated last time and what it would generate now
+// Returns the status of each file, in the order given
+func Check(files []File) ([]Drift, error) {
+	sums, err := readChecksums()
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := make([]Drift, 0, len(files))
+	for _, file := range files {
+		relPath, err := relativePath(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		recorded, tracked := sums.Files[relPath]
+
+		status := StatusUnchanged
+		current, err := os.ReadFile(file.Path)
+		switch {
+		case os.IsNotExist(err):
+			status = StatusMissing
+		case err != nil:
+			return nil, util.HandleError("Failed to read "+file.Path, err)
+		case !tracked:
+			status = StatusUntracked
+		case checksum(current) != recorded:
+			status = StatusModified
+		case checksum(file.Content) != recorded:
+			status = StatusOutdated
+		}
+		drifts = append(drifts, Drift{Path: file.Path, Status: status})
+	}
+	return drifts, nil
+}
+
+// resolve decides what to do with a generated file that was changed by hand
+// Returns the content to write, or true if the file must be kept as it is
+func resolve(file File, relPath string, current []byte, tracked bool) ([]byte, bool, error) {
+	// The content generated last time is needed to tell the changes made by hand from the new ones
+	var base []byte
+	if tracked {
+		base, _ = os.ReadFile(filepath.Join(config.GetGeneratedDirPath(), relPath))
+	}
+	options := Resolutions
+	if base == nil {
+		options = []string{ResolutionKeep, ResolutionOverwrite}
+	}
+
+	// Nothing to merge when only the file changed
+	choice := resolution
+	if base != nil && bytes.Equal(base, file.Content) && choice != ResolutionOverwrite {
+		util.PrintVerbose("Kept the changes made to " + file.Path)
+		return nil, true, nil
+	}
+	if choice == ResolutionMerge && base == nil {
+		util.PrintWarning("Cannot merge " + file.Path + " because the content generated last time is unknown")
+		choice = ResolutionAsk
+	}
+	if choice == ResolutionAsk {
+		util.PrintWarning(file.Path + " was changed since Tulip generated it")
+		if !util.IsInteractive() {
+			util.PrintWarning("Keeping it, run 'tulip init --on-conflict " + strings.Join(options[1:], "|") + "' to update it")
+			return nil, true, nil
+		}
+		printChanges(file, current, base)
+
+		var err error
+		choice, err = util.Choose("Keep your version, overwrite it or merge the changes?", options, ResolutionKeep)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	switch choice {
+	case ResolutionOverwrite:
+		return file.Content, false, nil
+	case ResolutionMerge:
+		merged, conflicts := util.Merge(string(base), string(current), string(file.Content), "your version", "new version")
+		if conflicts {
+			util.PrintWarning("Conflicting changes in " + file.Path + ", look for <<<<<<< markers to resolve them")
+		}
+		return []byte(merged), false, nil
+	default:
+		util.PrintInfo("Kept " + file.Path)
+		return nil, true, nil
+	}
+}
+
+// printChanges shows the changes made by hand to a generated file along with the new ones
+// Without the content generated last time, the file is compared with the new content instead
+func printChanges(file File, current []byte, base []byte) {
+	diffs := []string{util.Diff(file.Path, file.Path+" (new)", string(current), string(file.Content))}
+	if base != nil {
+		util.PrintInfo("Your changes, then the changes made by Tulip:")
+		diffs = []string{
+			util.Diff(file.Path+" (generated)", file.Path, string(base), string(current)),
+			util.Diff(file.Path+" (generated)", file.Path+" (new)", string(base), string(file.Content)),
+		}
+	}
+	for _, diff := range diffs {
+		for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+			util.PrintInfo(line)
+		}
+	}
+	util.PrintEmpty()
+}
+
+// readChecksums reads the checksums of the generated files, none if they were never recorded
+func readChecksums() (*checksums, error) {
+	sums := &checksums{Files: make(map[string]string)}
+	content, err := os.ReadFile(filepath.Join(config.GetGeneratedDirPath(), config.ConfigChecksumsFile))
+	if os.IsNotExist(err) {
+		return sums, nil
+	} else if err != nil {
+		return nil, util.HandleError("Failed to read checksums of generated files", err)
+	}
+	if err := yaml.Unmarshal(content, sums); err != nil {
+		return nil, util.HandleError("Failed to parse checksums of generated files", err)
+	}
+	if sums.Files == nil {
+		sums.Files = make(map[string]string)
+	}
+	return sums, nil
+}
+
+// writeChecksums records the checksums of the generated files
+func writeChecksums(sums *checksums) error {
+	content, err := yaml.Marshal(sums)
+	if err != nil {
+		return util.HandleError("Failed to encode checksums of generated files", err)
+	}
+	generatedDir := config.GetGeneratedDirPath()
+	if err := os.MkdirAll(generatedDir, 0755); err != nil {
+		return util.HandleError("Failed to create directory "+generatedDir, err)
+	}
+	return util.WriteFileAtomic(filepath.Join(generatedDir, config.ConfigChecksumsFile), content, 0644)
+}
+
+// relativePath returns the path of a generated file relative to Tulip's directory
+func relativePath(path string) (string, error) {
+	relPath, err := filepath.Rel(config.GetTulipDirPath(), path)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return "", util.HandleError("Generated file is outside of Tulip's directory: "+path, err)
+	}
+	return filepath.ToSlash(relPath), nil
+}
+
+// checksum returns the SHA-256 checksum of a content
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
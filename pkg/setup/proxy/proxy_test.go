@@ -0,0 +1,59 @@
+package proxy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+func TestCheck(t *testing.T) {
+	t.Setenv(config.EnvHome, t.TempDir())
+	config.Set(config.DefaultConfig())
+	if err := Initialize(); err != nil {
+		t.Fatalf("Initialize returned %v", err)
+	}
+	if err := Check(); err != nil {
+		t.Fatalf("Check returned %v right after Initialize", err)
+	}
+	composeFile := filepath.Join(config.GetProxyConfigDirPath(), config.ProxyDockerComposeFile)
+
+	// Files changed by hand are used as they are
+	content, err := os.ReadFile(composeFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(composeFile, append(content, "# Changed by hand\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(); err != nil {
+		t.Errorf("Check with a file changed by hand returned %v", err)
+	}
+	if err := os.WriteFile(composeFile, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Overrides that weren't rendered make the files outdated
+	t.Setenv(config.EnvName("docker.networkName"), "other")
+	if _, err := config.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(); !errors.Is(err, util.ErrOutdated) {
+		t.Errorf("Check with an override that wasn't rendered returned %v, want ErrOutdated", err)
+	}
+	os.Unsetenv(config.EnvName("docker.networkName"))
+	if _, err := config.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Missing files aren't generated on the fly
+	if err := os.Remove(filepath.Join(config.GetProxyConfigDirPath(), config.ProxyTraefikFile)); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(); !errors.Is(err, util.ErrNotInitialized) {
+		t.Errorf("Check with a missing file returned %v, want ErrNotInitialized", err)
+	}
+}
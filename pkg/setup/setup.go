@@ -2,61 +2,95 @@
 package setup
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/pierrestoffe/tulip/pkg/config"
 	"github.com/pierrestoffe/tulip/pkg/proxy"
+	"github.com/pierrestoffe/tulip/pkg/setup/generated"
 	proxySetup "github.com/pierrestoffe/tulip/pkg/setup/proxy"
 	sshSetup "github.com/pierrestoffe/tulip/pkg/setup/ssh"
+	"github.com/pierrestoffe/tulip/pkg/templates"
 	"github.com/pierrestoffe/tulip/pkg/util"
 )
 
-// Contains the template for the config
-const configTemplate = `version: {{.Version}}
-
-docker:
-    sock: {{.DockerSock}}
-    projectName: {{.ProjectName}}
-    networkName: {{.NetworkName}}
-proxy:
-    imageName: {{.ProxyImageName}}
-    httpPort: {{.HTTPPort}}
-    httpsPort: {{.HTTPSPort}}
-    adminPort: {{.AdminPort}}
-ssh:
-    imageName: {{.SSHImageName}}
-    port: {{.SSHPort}}`
+// Shown when required files are missing
+const repairHint = "Please run 'tulip init' to repair\nNote that the files generated by Tulip may be overwritten in the process"
+
+// Options controls how Initialize sets Tulip up
+type Options struct {
+	Force      bool   // Reinitialize from the default configuration without asking
+	Yes        bool   // Reinitialize without asking, keeping the current configuration
+	NoStart    bool   // Don't start the proxy once initialized
+	OnConflict string // How to handle generated files changed by hand, see generated.Resolutions
+	Seed       Seed   // Configuration values to set while initializing
+}
+
+// Seed holds configuration values set while initializing, empty values are left unchanged
+type Seed struct {
+	HTTPPort    string
+	HTTPSPort   string
+	AdminPort   string
+	SSHPort     string
+	NetworkName string
+	TLD         string
+}
 
 // Initializes the Tulip application environment
 // It creates necessary directories, extracts configuration files,
 // and starts the proxy service. If Tulip is already initialized,
-// it prompts the user for confirmation before reinitializing.
-func Initialize() error {
-	tulipDir, err := config.GetTulipDir()
-	if err == nil {
+// it prompts the user for confirmation before reinitializing,
+// unless the options already answer the question.
+func Initialize(opts Options) error {
+	if err := opts.Seed.validate(); err != nil {
+		return err
+	}
+	if opts.OnConflict != "" && !slices.Contains(generated.Resolutions, opts.OnConflict) {
+		return util.NewError(util.ErrUsage, "Invalid value for --on-conflict: "+opts.OnConflict, nil,
+			"Expected one of "+strings.Join(generated.Resolutions, ", "))
+	}
+
+	// Starting over also replaces the generated files changed by hand, unless told otherwise
+	resolution := opts.OnConflict
+	if resolution == "" && opts.Force {
+		resolution = generated.ResolutionOverwrite
+	}
+	generated.SetResolution(resolution)
+
+	dirs, err := config.ResolveDirs()
+	if err != nil {
+		return err
+	}
+	tulipDir := dirs.Config
+	// The directory may already hold ejected templates, so only an existing configuration file means Tulip is set up
+	if _, err := os.Stat(config.GetConfigFilePath()); err == nil && !opts.Force && !opts.Yes {
+		// Nobody can answer the question, so only the flags can
+		if !util.IsInteractive() {
+			return util.NewError(util.ErrUsage, "Tulip is already initialized at "+tulipDir, nil,
+				"Run 'tulip init --yes' to reinitialize, or 'tulip init --force' to start over from the default configuration")
+		}
 		util.PrintWarning("Tulip is already initialized at " + tulipDir)
-		util.PrintWarning("Do you want to reinitialize? This will overwrite existing configuration. (y/N)")
 
 		// Get user confirmation
-		var confirm string
-		if _, err := fmt.Scanln(&confirm); err != nil {
-			return util.HandleError("Failed to read user input", err)
+		confirm, err := util.Confirm("Do you want to reinitialize? This will overwrite existing configuration.")
+		if err != nil {
+			return err
 		}
-		util.PrintEmpty()
 
-		if confirm != "y" && confirm != "Y" {
-			if err := proxy.Start(); err != nil {
-				return err
+		if !confirm {
+			if opts.NoStart {
+				return nil
 			}
-			return nil
+			return proxy.Start()
 		}
 	}
 
 	util.PrintInfo("Initializing Tulip..")
 
-	if err := addConfigFiles(tulipDir); err != nil {
+	if err := addConfigFiles(tulipDir, opts); err != nil {
 		return err
 	}
 
@@ -64,14 +98,116 @@ func Initialize() error {
 	util.PrintEmpty()
 
 	// Start or restart the proxy service
-	if err := proxy.Restart(); err != nil {
+	if opts.NoStart {
+		return nil
+	}
+	return proxy.Restart()
+}
+
+// Render generates the content of the proxy and SSH files from the current configuration, without writing them
+func Render() ([]generated.File, error) {
+	files := make([]generated.File, 0)
+	for _, render := range []func() ([]generated.File, error){proxySetup.Render, sshSetup.Render} {
+		rendered, err := render()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, rendered...)
+	}
+	return files, nil
+}
+
+// Write writes generated files, keeping the changes made to them by hand unless told otherwise
+func Write(files []generated.File, onConflict string) error {
+	if onConflict != "" && !slices.Contains(generated.Resolutions, onConflict) {
+		return util.NewError(util.ErrUsage, "Invalid value for --on-conflict: "+onConflict, nil,
+			"Expected one of "+strings.Join(generated.Resolutions, ", "))
+	}
+	generated.SetResolution(onConflict)
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0755); err != nil {
+			return util.HandleError("Failed to create directory "+filepath.Dir(file.Path), err)
+		}
+		if err := generated.Write(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckGenerated reports the generated files that were changed by hand, are missing or would be generated differently
+// Returns an error if any file differs from what Tulip generates
+func CheckGenerated() error {
+	files, err := Render()
+	if err != nil {
+		return err
+	}
+
+	drifts, err := generated.Check(files)
+	if err != nil {
 		return err
 	}
+	changed := 0
+	for _, drift := range drifts {
+		if drift.Status == generated.StatusUnchanged {
+			util.PrintSuccess(drift.Path + ": " + drift.Status)
+			continue
+		}
+		changed++
+		util.PrintWarning(drift.Path + ": " + drift.Status)
+	}
+	util.AddResult("files", drifts)
+
+	if changed > 0 {
+		return util.NewError(util.ErrOutdated, strconv.Itoa(changed)+" generated file(s) differ from what Tulip generates", nil,
+			"Run 'tulip init --yes' to update them, your changes will be offered for merging")
+	}
+	return nil
+}
+
+// apply sets the non-empty seed values on a configuration
+func (s Seed) apply(cfg *config.Config) {
+	for _, value := range []struct {
+		seed   string
+		target *string
+	}{
+		{s.HTTPPort, &cfg.Proxy.HTTPPort},
+		{s.HTTPSPort, &cfg.Proxy.HTTPSPort},
+		{s.AdminPort, &cfg.Proxy.AdminPort},
+		{s.SSHPort, &cfg.SSH.Port},
+		{s.NetworkName, &cfg.Docker.NetworkName},
+		{s.TLD, &cfg.DNS.TLD},
+	} {
+		if value.seed != "" {
+			*value.target = value.seed
+		}
+	}
+}
+
+// validate checks that the seeded ports are valid port numbers
+func (s Seed) validate() error {
+	for name, port := range map[string]string{
+		"HTTP port":  s.HTTPPort,
+		"HTTPS port": s.HTTPSPort,
+		"admin port": s.AdminPort,
+		"SSH port":   s.SSHPort,
+	} {
+		if port == "" {
+			continue
+		}
+		if number, err := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+			return util.NewError(util.ErrUsage, "Invalid "+name+": "+port, nil)
+		}
+	}
 	return nil
 }
 
 // Ensure checks if all required directories and files exist
 func Ensure() error {
+	if _, err := config.ResolveDirs(); err != nil {
+		return err
+	}
+
 	// Check required directories
 	requiredDirs := []string{
 		config.GetTulipDirPath(),
@@ -83,18 +219,15 @@ func Ensure() error {
 
 	for _, dir := range requiredDirs {
 		if _, err := os.Stat(dir); os.IsNotExist(err) {
-			util.HandleError("Directory missing: "+dir, nil)
-			util.PrintWarning("Please run 'tulip init' to repair")
-			util.PrintWarning("Note that the files in ~/.tulip may be overwritten in the process")
-			return err
+			return util.NewError(util.ErrNotInitialized, "Directory missing: "+dir, nil, repairHint)
 		} else if err != nil {
-			return util.HandleError("Error accessing directory: "+dir, nil)
+			return util.HandleError("Error accessing directory: "+dir, err)
 		}
 	}
 
 	// Check required files
 	requiredFiles := []string{
-		filepath.Join(config.GetTulipDirPath(), config.ConfigFile),
+		config.GetConfigFilePath(),
 		filepath.Join(config.GetProxyConfigDirPath(), config.ProxyDockerComposeFile),
 		filepath.Join(config.GetProxyConfigDirPath(), config.ProxyTraefikFile),
 		filepath.Join(config.GetSSHConfigDirPath(), config.SSHDockerComposeFile),
@@ -103,12 +236,9 @@ func Ensure() error {
 
 	for _, file := range requiredFiles {
 		if _, err := os.Stat(file); os.IsNotExist(err) {
-			util.HandleError("Required file missing: "+file, nil)
-			util.PrintWarning("Please run 'tulip init' to repair")
-			util.PrintWarning("Note that the files in ~/.tulip may be overwritten in the process")
-			return err
+			return util.NewError(util.ErrNotInitialized, "Required file missing: "+file, nil, repairHint)
 		} else if err != nil {
-			return util.HandleError("Error accessing file: "+file, nil)
+			return util.HandleError("Error accessing file: "+file, err)
 		}
 	}
 
@@ -117,25 +247,21 @@ func Ensure() error {
 
 // addConfigFiles creates all necessary configuration files in the specified directory
 // Returns an error if any file creation or directory setup fails
-func addConfigFiles(tulipHomePath string) error {
-	// Get configuration
-	cfg, err := config.Initialize()
-	if err != nil {
-		return util.HandleError("Failed to load configuration", err)
+func addConfigFiles(tulipHomePath string, opts Options) error {
+	// Get the configuration saved in the file, starting over from the defaults when forced
+	// Environment variables and --set flags only apply to the current command, so they aren't saved
+	fileCfg := config.DefaultConfig()
+	if !opts.Force {
+		var err error
+		if fileCfg, err = config.LoadFile(); err != nil {
+			return util.HandleError("Failed to load configuration", err)
+		}
 	}
 
-	// Prepare template data
-	templateData := map[string]string{
-		"Version":        config.ConfigVersion,
-		"DockerSock":     cfg.Docker.Sock,
-		"ProjectName":    cfg.Docker.ProjectName,
-		"NetworkName":    cfg.Docker.NetworkName,
-		"ProxyImageName": cfg.Proxy.ImageName,
-		"HTTPPort":       cfg.Proxy.HTTPPort,
-		"HTTPSPort":      cfg.Proxy.HTTPSPort,
-		"AdminPort":      cfg.Proxy.AdminPort,
-		"SSHImageName":   cfg.SSH.ImageName,
-		"SSHPort":        cfg.SSH.Port,
+	// Apply the seeded values
+	opts.Seed.apply(fileCfg)
+	if err := config.Validate(fileCfg); err != nil {
+		return err
 	}
 
 	// Create directories
@@ -149,11 +275,20 @@ func addConfigFiles(tulipHomePath string) error {
 	}
 
 	// Create config.yml
-	configFilePath := filepath.Join(tulipHomePath, config.ConfigFile)
-	if err := util.CreateFileFromTemplate(configFilePath, configTemplate, templateData); err != nil {
+	configFilePath := config.GetConfigFilePath()
+	configTemplate, err := templates.Read(templates.Config)
+	if err != nil {
+		return err
+	}
+	if err := util.CreateFileFromTemplate(configFilePath, configTemplate, templates.NewData(fileCfg), util.FileOptions{Backup: true}); err != nil {
 		return err
 	}
 
+	// The other files are generated from the effective configuration, like 'tulip render' does
+	if _, err := config.Initialize(); err != nil {
+		return util.HandleError("Failed to load configuration", err)
+	}
+
 	// Add setup files
 	if err := proxySetup.Initialize(); err != nil {
 		return err
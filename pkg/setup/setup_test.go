@@ -0,0 +1,74 @@
+package setup
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// initialize sets Tulip up in a temporary directory without starting the proxy
+func initialize(t *testing.T, opts Options) {
+	t.Helper()
+	opts.NoStart = true
+	if err := Initialize(opts); err != nil {
+		t.Fatalf("Initialize returned %v", err)
+	}
+}
+
+func TestInitializeAlreadyInitialized(t *testing.T) {
+	t.Setenv(config.EnvHome, t.TempDir())
+	initialize(t, Options{})
+	before, err := os.ReadFile(config.GetConfigFilePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tests don't run on a terminal, so nobody can confirm reinitializing
+	err = Initialize(Options{NoStart: true, Seed: Seed{HTTPPort: "8081"}})
+	if !errors.Is(err, util.ErrUsage) {
+		t.Errorf("Initialize returned %v, want ErrUsage", err)
+	}
+	if after, _ := os.ReadFile(config.GetConfigFilePath()); string(after) != string(before) {
+		t.Errorf("configuration changed without confirmation:\n%s", after)
+	}
+
+	initialize(t, Options{Yes: true, Seed: Seed{HTTPPort: "8081"}})
+	cfg, err := config.Initialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Proxy.HTTPPort != "8081" {
+		t.Errorf("proxy.httpPort = %s after reinitializing with --yes, want 8081", cfg.Proxy.HTTPPort)
+	}
+}
+
+func TestInitializeSavesSeedOnly(t *testing.T) {
+	t.Setenv(config.EnvHome, t.TempDir())
+	t.Setenv(config.EnvName("proxy.httpsPort"), "9443")
+	if err := config.SetOverrides(map[string]string{"proxy.adminPort": "9850"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { config.SetOverrides(nil) })
+
+	initialize(t, Options{Seed: Seed{HTTPPort: "8081"}})
+
+	saved, err := config.LoadFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaults := config.DefaultConfig()
+	if saved.Proxy.HTTPPort != "8081" || saved.Proxy.HTTPSPort != defaults.Proxy.HTTPSPort || saved.Proxy.AdminPort != defaults.Proxy.AdminPort {
+		t.Errorf("saved ports = %s, %s, %s, want the seeded HTTP port only", saved.Proxy.HTTPPort, saved.Proxy.HTTPSPort, saved.Proxy.AdminPort)
+	}
+
+	effective, err := config.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if effective.Proxy.HTTPSPort != "9443" || effective.Proxy.AdminPort != "9850" {
+		t.Errorf("effective ports = %s, %s, want the overrides", effective.Proxy.HTTPSPort, effective.Proxy.AdminPort)
+	}
+}
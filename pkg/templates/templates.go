@@ -0,0 +1,172 @@
+// Package templates provides the templates of the files generated by Tulip, built into the executable
+// and overridable by the user
+package templates
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+// Names of the templates, relative to the template directories
+const (
+	Config        = "config.yml"               // Tulip's configuration file
+	ProxyCompose  = "proxy/docker-compose.yml" // Docker Compose file of the proxy
+	ProxyTraefik  = "proxy/traefik.yml"        // Traefik configuration file
+	SSHCompose    = "ssh/docker-compose.yml"   // Docker Compose file of the SSH service
+	SSHDockerfile = "ssh/Dockerfile"           // Dockerfile of the SSH service
+)
+
+// Sources of a template
+const (
+	SourceBuiltIn  = "built-in" // Template built into the executable
+	SourceOverride = "override" // Template found in Tulip's templates directory
+)
+
+// Directory of the built-in templates in the embedded file system
+const builtInRoot = "files"
+
+//go:embed files
+var builtIn embed.FS
+
+// Template describes a template along with the source it's read from
+type Template struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Path   string `json:"path"` // Path of the override, whether it exists or not
+}
+
+// Data holds the values the templates are rendered from
+type Data struct {
+	*config.Config             // Configuration values, e.g. {{.Proxy.HTTPPort}}
+	Version             string // Schema version of the configuration file
+	PublishedHTTPPort   string // Port published for HTTP, which rootless runtimes may remap
+	PublishedHTTPSPort  string // Port published for HTTPS, which rootless runtimes may remap
+	HTTPEntrypointPort  string // Port Traefik listens on for HTTP inside the proxy container
+	HTTPSEntrypointPort string // Port Traefik listens on for HTTPS inside the proxy container
+	Socket              string // Socket of the container runtime
+	Rootless            bool   // The container runtime runs without root privileges
+	CertsDir            string // Directory holding the certificates
+	CertsMountPath      string // Path where the certificates are mounted in the proxy container
+}
+
+// NewData gathers the values the templates are rendered from
+func NewData(cfg *config.Config) Data {
+	httpPort, httpsPort := cfg.PublishedPorts()
+	return Data{
+		Config:              cfg,
+		Version:             config.ConfigVersion,
+		PublishedHTTPPort:   httpPort,
+		PublishedHTTPSPort:  httpsPort,
+		HTTPEntrypointPort:  config.ProxyHTTPEntrypoint,
+		HTTPSEntrypointPort: config.ProxyHTTPSEntrypoint,
+		Socket:              cfg.Docker.Socket(),
+		Rootless:            cfg.Docker.IsRootless(),
+		CertsDir:            config.GetCertsConfigDirPath(),
+		CertsMountPath:      config.CertsMountPath,
+	}
+}
+
+// Render generates the content of a file from its template and a configuration
+// Returns an error if the template refers to a value Data doesn't have
+func Render(name string, cfg *config.Config) ([]byte, error) {
+	content, err := Read(name)
+	if err != nil {
+		return nil, err
+	}
+	return util.RenderTemplate(name, content, NewData(cfg))
+}
+
+// Names returns the names of the built-in templates, in alphabetical order
+func Names() []string {
+	names := make([]string, 0)
+	fs.WalkDir(builtIn, builtInRoot, func(filePath string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			names = append(names, filePath[len(builtInRoot)+1:])
+		}
+		return err
+	})
+	slices.Sort(names)
+	return names
+}
+
+// List describes every template along with the source it's read from
+func List() []Template {
+	list := make([]Template, 0)
+	for _, name := range Names() {
+		template := Template{Name: name, Source: SourceBuiltIn, Path: GetOverridePath(name)}
+		if _, err := os.Stat(template.Path); err == nil {
+			template.Source = SourceOverride
+		}
+		list = append(list, template)
+	}
+	return list
+}
+
+// Read returns the content of a template, from its override if there is one
+// Returns an ErrUsage error if the template doesn't exist
+func Read(name string) (string, error) {
+	if !slices.Contains(Names(), name) {
+		return "", unknownTemplateError(name)
+	}
+	content, err := os.ReadFile(GetOverridePath(name))
+	switch {
+	case err == nil:
+		util.PrintVerbose("Using template override " + GetOverridePath(name))
+		return string(content), nil
+	case !os.IsNotExist(err):
+		return "", util.HandleError("Failed to read template override "+GetOverridePath(name), err)
+	}
+	return ReadBuiltIn(name)
+}
+
+// ReadBuiltIn returns the content of a built-in template, ignoring its override
+// Returns an ErrUsage error if the template doesn't exist
+func ReadBuiltIn(name string) (string, error) {
+	if !slices.Contains(Names(), name) {
+		return "", unknownTemplateError(name)
+	}
+	content, err := builtIn.ReadFile(path.Join(builtInRoot, name))
+	if err != nil {
+		return "", util.HandleError("Failed to read built-in template "+name, err)
+	}
+	return string(content), nil
+}
+
+// Eject copies a built-in template to its override path, where it can be customized
+// Returns the path of the override, or an ErrUsage error if it already exists and force is false
+func Eject(name string, force bool) (string, error) {
+	content, err := ReadBuiltIn(name)
+	if err != nil {
+		return "", err
+	}
+
+	overridePath := GetOverridePath(name)
+	if _, err := os.Stat(overridePath); err == nil && !force {
+		return "", util.NewError(util.ErrUsage, "Template is already ejected to "+overridePath, nil,
+			"Use --force to replace it with the built-in template")
+	}
+	if err := os.MkdirAll(filepath.Dir(overridePath), 0755); err != nil {
+		return "", util.HandleError("Failed to create directory "+filepath.Dir(overridePath), err)
+	}
+	if err := util.CreateFile(overridePath, []byte(content), util.FileOptions{Backup: force}); err != nil {
+		return "", err
+	}
+	return overridePath, nil
+}
+
+// GetOverridePath constructs the full path of the file overriding a template
+func GetOverridePath(name string) string {
+	return filepath.Join(config.GetTemplatesDirPath(), filepath.FromSlash(name))
+}
+
+// unknownTemplateError reports a template name that isn't one of the built-in templates
+func unknownTemplateError(name string) error {
+	return util.NewError(util.ErrUsage, "Unknown template: "+name, nil, "Run 'tulip templates list' to see the available templates")
+}
@@ -0,0 +1,44 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pierrestoffe/tulip/pkg/config"
+	"github.com/pierrestoffe/tulip/pkg/util"
+)
+
+func TestRenderBuiltIn(t *testing.T) {
+	t.Setenv(config.EnvHome, t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.Docker.NetworkName = "custom-network"
+
+	for _, name := range Names() {
+		if _, err := Render(name, cfg); err != nil {
+			t.Errorf("Render(%s) returned %v", name, err)
+		}
+	}
+
+	traefik, err := Render(ProxyTraefik, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"network: custom-network",
+		`address: ":` + config.ProxyHTTPEntrypoint + `"`,
+		`address: ":` + config.ProxyHTTPSEntrypoint + `"`,
+		`directory: "` + config.CertsMountPath + `"`,
+	} {
+		if !strings.Contains(string(traefik), want) {
+			t.Errorf("traefik.yml doesn't contain %q:\n%s", want, traefik)
+		}
+	}
+}
+
+func TestRenderUnknownValue(t *testing.T) {
+	t.Setenv(config.EnvHome, t.TempDir())
+	data := NewData(config.DefaultConfig())
+	if _, err := util.RenderTemplate("test", "port: {{.Proxy.Bogus}}\n", data); err == nil {
+		t.Error("RenderTemplate succeeded with a value Data doesn't have")
+	}
+}
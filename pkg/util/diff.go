@@ -0,0 +1,142 @@
+// Package util provides a line-based diff of text files
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Number of unchanged lines shown around each change
+const diffContext = 3
+
+// Diff returns the unified diff turning oldText into newText, or an empty string if they are equal
+// Parameters:
+//   - oldName: the label of the original text, e.g. its file path
+//   - newName: the label of the changed text
+//   - oldText: the original text
+//   - newText: the changed text
+func Diff(oldName string, newName string, oldText string, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+	ops := diffLines(oldLines, newLines)
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", oldName, newName)
+
+	// Group the operations into hunks separated by more than twice the context
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		hunkStart := max(start-diffContext, 0)
+
+		// Extend the hunk while changes are close enough to each other
+		end := start
+		for end < len(ops) {
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContext {
+				break
+			}
+			for next < len(ops) && ops[next].kind != ' ' {
+				next++
+			}
+			end = next
+		}
+		hunkEnd := min(end+diffContext, len(ops))
+
+		writeHunk(&builder, ops[hunkStart:hunkEnd])
+		start = hunkEnd
+	}
+	return builder.String()
+}
+
+// diffOp is a line of a diff: kept (' '), removed ('-') or added ('+')
+type diffOp struct {
+	kind    byte
+	line    string
+	oldLine int // Line number in the original text, starting at 1
+	newLine int // Line number in the changed text, starting at 1
+}
+
+// diffLines computes the operations turning oldLines into newLines using their longest common subsequence
+func diffLines(oldLines []string, newLines []string) []diffOp {
+	// lengths[i][j] holds the length of the longest common subsequence of oldLines[i:] and newLines[j:]
+	lengths := make([][]int, len(oldLines)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(oldLines)+len(newLines))
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			ops = append(ops, diffOp{kind: ' ', line: oldLines[i], oldLine: i + 1, newLine: j + 1})
+			i++
+			j++
+		case i < len(oldLines) && (j == len(newLines) || lengths[i+1][j] >= lengths[i][j+1]):
+			// Removed lines come before added ones
+			ops = append(ops, diffOp{kind: '-', line: oldLines[i], oldLine: i + 1, newLine: j + 1})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: newLines[j], oldLine: i + 1, newLine: j + 1})
+			j++
+		}
+	}
+	return ops
+}
+
+// writeHunk writes a hunk header followed by its lines
+func writeHunk(builder *strings.Builder, ops []diffOp) {
+	oldStart, newStart := ops[0].oldLine, ops[0].newLine
+	oldCount, newCount := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+	}
+	// Empty ranges start at the line before, as in diff -u
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+
+	fmt.Fprintf(builder, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, op := range ops {
+		builder.WriteByte(op.kind)
+		builder.WriteString(op.line)
+		builder.WriteByte('\n')
+	}
+}
+
+// splitLines splits a text into lines, ignoring the final line break
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,95 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// lines joins lines, each followed by a line break
+func lines(values ...string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.Join(values, "\n") + "\n"
+}
+
+// numberedLines returns the numbers from 1 to n, one per line, with some of them replaced
+func numberedLines(n int, replaced map[string]string) string {
+	values := make([]string, n)
+	for i := range values {
+		values[i] = strconv.Itoa(i + 1)
+		if replacement, ok := replaced[values[i]]; ok {
+			values[i] = replacement
+		}
+	}
+	return lines(values...)
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    string
+	}{
+		{
+			name:    "equal texts",
+			oldText: lines("a", "b"),
+			newText: lines("a", "b"),
+			want:    "",
+		},
+		{
+			name:    "insert only",
+			oldText: lines("a", "b", "c"),
+			newText: lines("a", "b", "x", "c"),
+			want:    lines("@@ -1,3 +1,4 @@", " a", " b", "+x", " c"),
+		},
+		{
+			name:    "delete only",
+			oldText: lines("a", "b", "c", "d"),
+			newText: lines("a", "d"),
+			want:    lines("@@ -1,4 +1,2 @@", " a", "-b", "-c", " d"),
+		},
+		{
+			name:    "insert into empty text",
+			oldText: "",
+			newText: lines("a", "b"),
+			want:    lines("@@ -0,0 +1,2 @@", "+a", "+b"),
+		},
+		{
+			name:    "delete whole text",
+			oldText: lines("a", "b"),
+			newText: "",
+			want:    lines("@@ -1,2 +0,0 @@", "-a", "-b"),
+		},
+		{
+			name:    "changes far apart make separate hunks",
+			oldText: numberedLines(20, nil),
+			newText: numberedLines(20, map[string]string{"2": "two", "15": "fifteen"}),
+			want: lines(
+				"@@ -1,5 +1,5 @@", " 1", "-2", "+two", " 3", " 4", " 5",
+				"@@ -12,7 +12,7 @@", " 12", " 13", " 14", "-15", "+fifteen", " 16", " 17", " 18",
+			),
+		},
+		{
+			name:    "changes close together share a hunk",
+			oldText: numberedLines(12, nil),
+			newText: numberedLines(12, map[string]string{"3": "three", "9": "nine"}),
+			want: lines(
+				"@@ -1,12 +1,12 @@", " 1", " 2", "-3", "+three", " 4", " 5", " 6", " 7", " 8", "-9", "+nine", " 10", " 11", " 12",
+			),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want := test.want
+			if want != "" {
+				want = "--- old\n+++ new\n" + want
+			}
+			if got := Diff("old", "new", test.oldText, test.newText); got != want {
+				t.Errorf("Diff =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
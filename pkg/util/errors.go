@@ -0,0 +1,104 @@
+// Package util provides the errors and exit codes shared by the Tulip application
+package util
+
+import (
+	"errors"
+	"strings"
+)
+
+// Categories of errors, checked with errors.Is
+var (
+	ErrUsage              = errors.New("invalid usage")
+	ErrNotInitialized     = errors.New("tulip is not initialized")
+	ErrConfigInvalid      = errors.New("configuration is invalid")
+	ErrRuntimeUnavailable = errors.New("container runtime is unavailable")
+	ErrPortInUse          = errors.New("port is already in use")
+	ErrOutdated           = errors.New("generated files don't match the configuration")
+)
+
+// Exit codes of the tulip command
+const (
+	ExitOK                 = 0 // Command succeeded
+	ExitFailure            = 1 // Command failed for any other reason
+	ExitUsage              = 2 // Flags or arguments are invalid
+	ExitNotInitialized     = 3 // Tulip isn't initialized, run 'tulip init'
+	ExitConfigInvalid      = 4 // Configuration file can't be read or is invalid
+	ExitRuntimeUnavailable = 5 // Container runtime isn't installed or isn't running
+	ExitPortInUse          = 6 // Port required by the proxy is already in use
+	ExitOutdated           = 7 // Generated files don't match the configuration, run 'tulip render'
+)
+
+// Error is an error with a message meant for display, wrapping its cause and category
+type Error struct {
+	Kind    error  // Category of the error, e.g. ErrConfigInvalid, may be nil
+	Message string // Message describing what failed
+	Err     error  // Underlying error, may be nil
+	Context string // Extra information displayed on its own line, may be empty
+}
+
+// NewError creates an error of the given category
+// Parameters:
+//   - kind: the category of the error, e.g. ErrPortInUse
+//   - message: the message describing what failed
+//   - err: the underlying error (may be nil)
+//   - additionalContext: optional extra information to append to the error
+func NewError(kind error, message string, err error, additionalContext ...string) error {
+	e := &Error{Kind: kind, Message: message, Err: err}
+	if len(additionalContext) > 0 {
+		e.Context = additionalContext[0]
+	}
+	return e
+}
+
+// Error returns the message followed by the underlying error and the context
+func (e *Error) Error() string {
+	var builder strings.Builder
+	builder.WriteString(e.Message)
+	if builder.Len() == 0 && e.Kind != nil {
+		builder.WriteString(e.Kind.Error())
+	}
+	if e.Err != nil {
+		if builder.Len() > 0 {
+			builder.WriteString(": ")
+		}
+		builder.WriteString(e.Err.Error())
+	}
+	if e.Context != "" {
+		builder.WriteString("\n" + e.Context)
+	}
+	return builder.String()
+}
+
+// Unwrap returns the category and the underlying error so errors.Is and errors.As can match either
+func (e *Error) Unwrap() []error {
+	wrapped := make([]error, 0, 2)
+	if e.Kind != nil {
+		wrapped = append(wrapped, e.Kind)
+	}
+	if e.Err != nil {
+		wrapped = append(wrapped, e.Err)
+	}
+	return wrapped
+}
+
+// ExitCode returns the exit code matching the category of an error
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrUsage):
+		return ExitUsage
+	case errors.Is(err, ErrNotInitialized):
+		return ExitNotInitialized
+	case errors.Is(err, ErrConfigInvalid):
+		return ExitConfigInvalid
+	case errors.Is(err, ErrRuntimeUnavailable):
+		return ExitRuntimeUnavailable
+	case errors.Is(err, ErrPortInUse):
+		return ExitPortInUse
+	case errors.Is(err, ErrOutdated):
+		return ExitOutdated
+	default:
+		return ExitFailure
+	}
+}
@@ -2,42 +2,161 @@
 package util
 
 import (
+	"bytes"
+	"cmp"
 	"os"
 	"path/filepath"
 	"text/template"
 )
 
-// CreateFileFromTemplate generates a new file from a template with the provided data
+// Permissions of the files written by Tulip
+const (
+	ModePublic  os.FileMode = 0644 // Files anyone can read, such as configuration files
+	ModePrivate os.FileMode = 0600 // Files only the current user can read, such as private keys
+)
+
+// Extension of the file keeping the previous content of a file
+const BackupFileExt = ".bak"
+
+// FileOptions controls how a file is written
+type FileOptions struct {
+	Mode   os.FileMode // Permissions of the file, ModePublic if zero
+	Backup bool        // Keep the previous content of the file next to it, see BackupFileExt
+}
+
+// CreateFileFromTemplate generates a file from a template with the provided data
+// The template is rendered before the file is touched, so a template error leaves the file as it was
 // Parameters:
 //   - destPath: target path where the file will be created
 //   - templateContent: the template string to process
-//   - data: key-value pairs to use in template processing
-func CreateFileFromTemplate(destPath string, templateContent string, data map[string]string) error {
-	// Use empty map if data is nil
-	templateData := data
-	if templateData == nil {
-		templateData = make(map[string]string)
+//   - data: the values used in template processing
+//   - opts: the permissions of the file and whether to back it up
+func CreateFileFromTemplate(destPath string, templateContent string, data any, opts FileOptions) error {
+	content, err := RenderTemplate(filepath.Base(destPath), templateContent, data)
+	if err != nil {
+		return err
 	}
+	return CreateFile(destPath, content, opts)
+}
 
+// RenderTemplate processes a template with the provided data
+// Referring to a value the data doesn't have is an error, whether it's a missing struct field or map key
+// Parameters:
+//   - name: the name of the template, used in error messages
+//   - templateContent: the template string to process
+//   - data: the values used in template processing
+func RenderTemplate(name string, templateContent string, data any) ([]byte, error) {
 	// Process file as template
-	tmpl, err := template.New(filepath.Base(destPath)).Parse(templateContent)
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(templateContent)
+	if err != nil {
+		return nil, HandleError("Failed to parse template for "+name, err)
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return nil, HandleError("Failed to render template for "+name, err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// CreateFile writes the given content to a file, replacing it atomically if it exists
+// Parameters:
+//   - destPath: target path where the file will be created
+//   - content: the content to write to the file
+//   - opts: the permissions of the file and whether to back it up
+func CreateFile(destPath string, content []byte, opts FileOptions) error {
+	if opts.Backup {
+		if err := backupFile(destPath, content); err != nil {
+			return err
+		}
+	}
+	if err := WriteFileAtomic(destPath, content, cmp.Or(opts.Mode, ModePublic)); err != nil {
+		return err
+	}
+
+	PrintVerbose("Created " + destPath)
+	return nil
+}
+
+// GetBackupFilePath returns the path of the file keeping the previous content of a file
+func GetBackupFilePath(path string) string {
+	return path + BackupFileExt
+}
+
+// backupFile copies a file next to it before it gets new content, replacing the previous backup
+// Nothing is copied if the file doesn't exist or already has the new content
+func backupFile(path string, newContent []byte) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return HandleError("Failed to back up "+path, err)
+	}
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return HandleError("Failed to parse template for"+destPath, err)
+		return HandleError("Failed to back up "+path, err)
+	}
+	if bytes.Equal(content, newContent) {
+		return nil
 	}
 
-	// Create destination file
-	f, err := os.Create(destPath)
+	backupPath := GetBackupFilePath(path)
+	if err := WriteFileAtomic(backupPath, content, info.Mode().Perm()); err != nil {
+		return err
+	}
+	PrintVerbose("Previous content of " + path + " saved to " + backupPath)
+	return nil
+}
+
+// UpdateFileAtomic replaces the content of a file like WriteFileAtomic, as if the file was edited in place
+// A symlinked file is written through the link, and the file keeps its permissions, new files getting ModePublic
+// Parameters:
+//   - path: path of the file, possibly a symlink
+//   - content: the new content of the file
+func UpdateFileAtomic(path string, content []byte) error {
+	destPath, err := filepath.EvalSymlinks(path)
+	if os.IsNotExist(err) {
+		return WriteFileAtomic(path, content, ModePublic)
+	} else if err != nil {
+		return HandleError("Failed to resolve "+path, err)
+	}
+	info, err := os.Stat(destPath)
 	if err != nil {
-		return HandleError("Failed to create file"+destPath, err)
+		return HandleError("Failed to read permissions of "+path, err)
 	}
-	defer f.Close()
+	return WriteFileAtomic(destPath, content, info.Mode().Perm())
+}
 
-	// Add content to destination file
-	err = tmpl.Execute(f, templateData)
+// WriteFileAtomic replaces the content of a file without ever leaving it partially written
+// The content is written to a temporary file in the same directory, synced to disk and renamed into place
+// Parameters:
+//   - destPath: target path of the file
+//   - content: the content to write to the file
+//   - perm: the permissions of the file
+func WriteFileAtomic(destPath string, content []byte, perm os.FileMode) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(destPath), "."+filepath.Base(destPath)+".*.tmp")
 	if err != nil {
-		return HandleError("Failed to add content to destination file"+destPath, err)
+		return HandleError("Failed to create temporary file for "+destPath, err)
 	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
 
-	PrintInfo("Created " + destPath)
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close()
+		return HandleError("Failed to write file "+destPath, err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return HandleError("Failed to write file "+destPath, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return HandleError("Failed to write file "+destPath, err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return HandleError("Failed to set permissions of "+destPath, err)
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		return HandleError("Failed to replace file "+destPath, err)
+	}
 	return nil
 }
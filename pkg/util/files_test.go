@@ -0,0 +1,68 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "config.yml")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "config.yml")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UpdateFileAtomic(link, []byte("new")); err != nil {
+		t.Fatalf("UpdateFileAtomic returned %v", err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("symlink was replaced: %v, %v", info, err)
+	}
+	content, err := os.ReadFile(target)
+	if err != nil || string(content) != "new" {
+		t.Errorf("target holds %q, %v, want \"new\"", content, err)
+	}
+	if info, err := os.Stat(target); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("target mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(target)); len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestUpdateFileAtomicNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := UpdateFileAtomic(path, []byte("new")); err != nil {
+		t.Fatalf("UpdateFileAtomic returned %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != ModePublic {
+		t.Errorf("mode = %v, %v, want %v", info.Mode().Perm(), err, ModePublic)
+	}
+}
+
+func TestRenderTemplateMissingValue(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+	}{
+		{"map key", map[string]string{"Port": "80"}},
+		{"struct field", struct{ Port string }{Port: "80"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := RenderTemplate("test", "port: {{.Port}}\nhost: {{.Host}}\n", test.data)
+			if err == nil {
+				t.Errorf("RenderTemplate succeeded with a missing value:\n%s", content)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Package util provides a line-based three-way merge of text files
+package util
+
+import (
+	"slices"
+	"strings"
+)
+
+// Markers surrounding the conflicting lines of a merge, as written by git
+const (
+	conflictStart     = "<<<<<<< "
+	conflictSeparator = "======="
+	conflictEnd       = ">>>>>>> "
+)
+
+// Merge combines the changes made to a common base text on two sides
+// Changes touching the same lines on both sides are kept between conflict markers
+// Returns the merged text and whether it holds conflicts
+// Parameters:
+//   - base: the text both sides started from
+//   - ours: the text changed on one side, e.g. by the user
+//   - theirs: the text changed on the other side, e.g. by a new template
+//   - oursName: the label of ours in conflict markers
+//   - theirsName: the label of theirs in conflict markers
+func Merge(base string, ours string, theirs string, oursName string, theirsName string) (string, bool) {
+	baseLines := splitLines(base)
+	oursChanges := diffChanges(baseLines, splitLines(ours))
+	theirsChanges := diffChanges(baseLines, splitLines(theirs))
+
+	merged := make([]string, 0, len(baseLines))
+	conflicts := false
+	pos, i, j := 0, 0, 0
+	for i < len(oursChanges) || j < len(theirsChanges) {
+		// Start a group with the next change, on whichever side it is
+		start := 0
+		if j == len(theirsChanges) || (i < len(oursChanges) && oursChanges[i].start <= theirsChanges[j].start) {
+			start = oursChanges[i].start
+		} else {
+			start = theirsChanges[j].start
+		}
+
+		// Add the changes of both sides touching the group
+		end := start
+		oursStart, theirsStart := i, j
+		for grouping := true; grouping; {
+			switch {
+			case i < len(oursChanges) && oursChanges[i].start <= end:
+				end = max(end, oursChanges[i].end)
+				i++
+			case j < len(theirsChanges) && theirsChanges[j].start <= end:
+				end = max(end, theirsChanges[j].end)
+				j++
+			default:
+				grouping = false
+			}
+		}
+		merged = append(merged, baseLines[pos:start]...)
+		oursLines := applyChanges(baseLines, start, end, oursChanges[oursStart:i])
+		theirsLines := applyChanges(baseLines, start, end, theirsChanges[theirsStart:j])
+		switch {
+		case theirsStart == j:
+			merged = append(merged, oursLines...)
+		case oursStart == i, slices.Equal(oursLines, theirsLines):
+			merged = append(merged, theirsLines...)
+		default:
+			conflicts = true
+			merged = append(merged, conflictStart+oursName)
+			merged = append(merged, oursLines...)
+			merged = append(merged, conflictSeparator)
+			merged = append(merged, theirsLines...)
+			merged = append(merged, conflictEnd+theirsName)
+		}
+		pos = end
+	}
+	merged = append(merged, baseLines[pos:]...)
+
+	if len(merged) == 0 {
+		return "", conflicts
+	}
+	return strings.Join(merged, "\n") + "\n", conflicts
+}
+
+// diffChange replaces the lines start to end (excluded) of a base text
+type diffChange struct {
+	start int
+	end   int
+	lines []string
+}
+
+// diffChanges groups the operations turning baseLines into otherLines into changes of the base
+func diffChanges(baseLines []string, otherLines []string) []diffChange {
+	changes := make([]diffChange, 0)
+	ops := diffLines(baseLines, otherLines)
+	line := 0
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			line++
+			k++
+			continue
+		}
+		change := diffChange{start: line, end: line}
+		for ; k < len(ops) && ops[k].kind != ' '; k++ {
+			if ops[k].kind == '-' {
+				change.end++
+				line++
+			} else {
+				change.lines = append(change.lines, ops[k].line)
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// applyChanges returns the lines start to end (excluded) of a base text once changed
+func applyChanges(baseLines []string, start int, end int, changes []diffChange) []string {
+	lines := make([]string, 0)
+	pos := start
+	for _, change := range changes {
+		lines = append(lines, baseLines[pos:change.start]...)
+		lines = append(lines, change.lines...)
+		pos = change.end
+	}
+	return append(lines, baseLines[pos:end]...)
+}
@@ -0,0 +1,126 @@
+package util
+
+import "testing"
+
+func TestMerge(t *testing.T) {
+	base := lines("a", "b", "c", "d", "e", "f")
+
+	tests := []struct {
+		name      string
+		base      string
+		ours      string
+		theirs    string
+		want      string
+		conflicts bool
+	}{
+		{
+			name:   "unchanged on both sides",
+			base:   base,
+			ours:   base,
+			theirs: base,
+			want:   base,
+		},
+		{
+			name:   "changed on one side only",
+			base:   base,
+			ours:   base,
+			theirs: lines("a", "B", "c", "d", "e", "f"),
+			want:   lines("a", "B", "c", "d", "e", "f"),
+		},
+		{
+			name:   "non-overlapping edits",
+			base:   base,
+			ours:   lines("A", "b", "c", "d", "e", "f"),
+			theirs: lines("a", "b", "c", "d", "e", "F"),
+			want:   lines("A", "b", "c", "d", "e", "F"),
+		},
+		{
+			name:   "non-overlapping insert and delete",
+			base:   base,
+			ours:   lines("a", "b", "x", "c", "d", "e", "f"),
+			theirs: lines("a", "b", "c", "d", "f"),
+			want:   lines("a", "b", "x", "c", "d", "f"),
+		},
+		{
+			name:   "identical edits on both sides",
+			base:   base,
+			ours:   lines("a", "B", "c", "d", "E", "f"),
+			theirs: lines("a", "B", "c", "d", "E", "f"),
+			want:   lines("a", "B", "c", "d", "E", "f"),
+		},
+		{
+			name:   "conflicting edits",
+			base:   base,
+			ours:   lines("a", "b", "mine", "d", "e", "f"),
+			theirs: lines("a", "b", "yours", "d", "e", "f"),
+			want: lines("a", "b",
+				"<<<<<<< ours", "mine", "=======", "yours", ">>>>>>> theirs",
+				"d", "e", "f"),
+			conflicts: true,
+		},
+		{
+			name:   "adjacent edits conflict like in git",
+			base:   base,
+			ours:   lines("a", "B", "c", "d", "e", "f"),
+			theirs: lines("a", "b", "C", "d", "e", "f"),
+			want: lines("a",
+				"<<<<<<< ours", "B", "c", "=======", "b", "C", ">>>>>>> theirs",
+				"d", "e", "f"),
+			conflicts: true,
+		},
+		{
+			name:   "different inserts at the same position",
+			base:   base,
+			ours:   lines("a", "b", "c", "mine", "d", "e", "f"),
+			theirs: lines("a", "b", "c", "yours", "d", "e", "f"),
+			want: lines("a", "b", "c",
+				"<<<<<<< ours", "mine", "=======", "yours", ">>>>>>> theirs",
+				"d", "e", "f"),
+			conflicts: true,
+		},
+		{
+			name:   "identical inserts at the same position",
+			base:   base,
+			ours:   lines("a", "b", "c", "x", "d", "e", "f"),
+			theirs: lines("a", "b", "c", "x", "d", "e", "f"),
+			want:   lines("a", "b", "c", "x", "d", "e", "f"),
+		},
+		{
+			name:   "empty base added on one side",
+			ours:   "",
+			theirs: lines("a", "b"),
+			want:   lines("a", "b"),
+		},
+		{
+			name:   "empty base added identically on both sides",
+			ours:   lines("a", "b"),
+			theirs: lines("a", "b"),
+			want:   lines("a", "b"),
+		},
+		{
+			name:      "empty base added differently on both sides",
+			ours:      lines("a"),
+			theirs:    lines("b"),
+			want:      lines("<<<<<<< ours", "a", "=======", "b", ">>>>>>> theirs"),
+			conflicts: true,
+		},
+		{
+			name:   "everything deleted on both sides",
+			base:   base,
+			ours:   "",
+			theirs: "",
+			want:   "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, conflicts := Merge(test.base, test.ours, test.theirs, "ours", "theirs")
+			if got != test.want {
+				t.Errorf("Merge =\n%s\nwant\n%s", got, test.want)
+			}
+			if conflicts != test.conflicts {
+				t.Errorf("Merge reported conflicts = %v, want %v", conflicts, test.conflicts)
+			}
+		})
+	}
+}
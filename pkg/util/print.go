@@ -4,7 +4,6 @@ package util
 
 import (
 	"fmt"
-	"strings"
 )
 
 const (
@@ -18,57 +17,70 @@ const (
 
 // Prints a message in the default terminal color
 func PrintInfo(message string) {
-	print(message, colorWhite)
+	emit(LevelInfo, message, false)
 }
 
 // Prints a message in green color to indicate success
 func PrintSuccess(message string) {
-	print(message, colorGreen)
+	emit(LevelSuccess, message, false)
+}
+
+// Prints data requested by the command, such as a configuration value, whatever the verbosity
+func PrintOutput(message string) {
+	emit(LevelOutput, message, false)
 }
 
 // Prints a message in yellow color to indicate a warning
 func PrintWarning(message string) {
-	print(message, colorYellow)
+	emit(LevelWarning, message, false)
+}
+
+// Prints a message in the default terminal color when verbose output is requested
+func PrintVerbose(message string) {
+	emit(LevelVerbose, message, false)
 }
 
 // Prints a message in red color to indicate an error
 func PrintError(message string) {
-	print(message, colorRed)
+	emit(LevelError, message, false)
 }
 
 // Prints a message in red color to indicate an error and returns the error
 func PrintErrorE(err error) error {
+	if p, ok := GetPrinter().(*TextPrinter); !ok || !p.Color {
+		return err
+	}
 	return fmt.Errorf("%s%v%s", colorRed, err, colorReset)
 }
 
-// Prints a message in blue color for debug information
+// Prints a message in blue color for debug information when debug output is requested
 func PrintDebug(message string) {
-	print(message, colorBlue)
+	emit(LevelDebug, message, false)
 }
 
 // Clears the previous line and prints a message in the default terminal color
 func PrintInfoReplace(message string) {
-	printReplace(message, colorWhite)
+	emit(LevelInfo, message, true)
 }
 
 // Clears the previous line and prints a message in green color
 func PrintSuccessReplace(message string) {
-	printReplace(message, colorGreen)
+	emit(LevelSuccess, message, true)
 }
 
 // Clears the previous line and prints a message in yellow color
 func PrintWarningReplace(message string) {
-	printReplace(message, colorYellow)
+	emit(LevelWarning, message, true)
 }
 
 // Clears the previous line and prints a message in red color
 func PrintErrorReplace(message string) {
-	printReplace(message, colorRed)
+	emit(LevelError, message, true)
 }
 
 // Clears the previous line and prints a message in blue color
 func PrintDebugReplace(message string) {
-	printReplace(message, colorBlue)
+	emit(LevelDebug, message, true)
 }
 
 // Prints an empty line
@@ -76,68 +88,14 @@ func PrintEmpty() {
 	PrintInfo("")
 }
 
-// HandleError formats and prints an error message with optional additional context
+// HandleError wraps an error with a message and optional additional context
+// The error isn't printed, which is left to the top level once the command has failed
 // Parameters:
 //   - message: The main error message to display
 //   - err: The underlying error (may be nil)
 //   - additionalContext: Optional extra information to append to the error
 //
-// Returns a formatted error that includes all provided information
+// Returns an error that includes all provided information and unwraps to err
 func HandleError(message string, err error, additionalContext ...string) error {
-	var formattedErr error
-	var displayMessage string
-
-	// Create a lowercase version of the message
-	messageLowercase := strings.ToLower(message)
-
-	// Check if we have additional context
-	hasContext := len(additionalContext) > 0 && additionalContext[0] != ""
-
-	// Handle different combinations of err and additionalContext
-	if err == nil {
-		if hasContext {
-			// Case: No error but with additional context
-			displayMessage = fmt.Sprintf("%s\n%s", message, additionalContext[0])
-			formattedErr = fmt.Errorf("%s\n%s", messageLowercase, additionalContext[0])
-		} else {
-			// Case: No error and no additional context
-			displayMessage = message
-			formattedErr = fmt.Errorf("%s", messageLowercase)
-		}
-	} else {
-		if hasContext {
-			// Case: Error with additional context
-			displayMessage = fmt.Sprintf("%s: %v\n%s", message, err, additionalContext[0])
-			formattedErr = fmt.Errorf("%s: %v\n%s", messageLowercase, err, additionalContext[0])
-		} else {
-			// Case: Error without additional context
-			displayMessage = fmt.Sprintf("%s: %v", message, err)
-			formattedErr = fmt.Errorf("%s: %v", messageLowercase, err)
-		}
-	}
-
-	// Print the error message
-	PrintError(displayMessage)
-
-	return formattedErr
-}
-
-// Outputs a message with the specified color to stdout
-func print(message string, color string) {
-	if color == "" {
-		fmt.Printf("%s\n", message)
-	} else {
-		fmt.Printf("%s%s%s\n", color, message, colorReset)
-	}
-}
-
-// Clears the previous line and prints a message with the specified color
-func printReplace(message string, color string) {
-	clearLine()
-	print(message, color)
-}
-
-// Moves the cursor up one line and clears it
-func clearLine() {
-	fmt.Print("\033[1A\033[K") // Move up one line and clear it
+	return NewError(nil, message, err, additionalContext...)
 }
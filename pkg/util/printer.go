@@ -0,0 +1,298 @@
+// Package util provides the printers used to display Tulip's output
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// Output formats supported by Tulip
+const (
+	OutputText = "text" // Colored lines meant for a terminal
+	OutputJSON = "json" // One JSON object per line meant for scripts and editors
+)
+
+// Outputs lists the supported output formats
+var Outputs = []string{OutputText, OutputJSON}
+
+// Level describes the severity of a message
+type Level string
+
+// Message levels
+const (
+	LevelInfo    Level = "info"
+	LevelSuccess Level = "success"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+	LevelVerbose Level = "verbose"
+	LevelDebug   Level = "debug"
+	LevelOutput  Level = "output" // Data requested by the command, displayed at any verbosity
+)
+
+// Verbosity selects which message levels are displayed
+type Verbosity int
+
+// Verbosities, each displaying the levels of the previous one
+const (
+	VerbosityQuiet   Verbosity = iota // Requested data, errors and warnings only
+	VerbosityNormal                   // Adds info and success messages
+	VerbosityVerbose                  // Adds details such as created files
+	VerbosityDebug                    // Adds debug information such as executed commands
+)
+
+// Event is a message emitted while a command runs
+type Event struct {
+	Level     Level  `json:"level"`
+	Message   string `json:"message"`
+	Component string `json:"component,omitempty"` // Command emitting the message, e.g. "proxy start"
+	Replace   bool   `json:"-"`                   // Replaces the previous message in text output
+}
+
+// Result summarizes the outcome of a command once it has run
+type Result struct {
+	Component string         `json:"component,omitempty"`
+	Success   bool           `json:"success"`
+	Error     string         `json:"error,omitempty"`
+	Data      map[string]any `json:"data,omitempty"` // Values reported by the command, e.g. listed hostnames
+}
+
+// Printer displays the events and result of a command
+type Printer interface {
+	// Event displays a message emitted while the command runs
+	Event(event Event)
+	// Result displays the outcome of the command
+	Result(result Result)
+}
+
+// TextPrinter displays messages as lines, colored and replaced in place on terminals
+type TextPrinter struct {
+	Out         io.Writer
+	Err         io.Writer // Receives warnings and errors
+	Color       bool      // Use ANSI colors
+	Interactive bool      // Replace previous lines using cursor movements
+
+	lastOut io.Writer // Writer that received the previous line
+}
+
+// JSONPrinter displays events and the result as JSON objects, one per line
+// Every object goes to the same writer so that scripts can read them in order
+type JSONPrinter struct {
+	Out io.Writer
+}
+
+var (
+	// State shared by the printing functions
+	printer      Printer = NewTextPrinter(os.Stdout, os.Stderr)
+	component    string
+	resultData   = make(map[string]any)
+	lastError    string
+	verbosity    = VerbosityNormal
+	printerMutex sync.Mutex
+
+	// Messages replace the last displayed one only if it's the one they follow up on
+	lineDisplayed    bool               // A message was displayed
+	lineVerbosity    Verbosity          // Verbosity needed to display the last displayed message
+	droppedVerbosity = noDroppedMessage // Lowest verbosity needed by the messages dropped since
+)
+
+// Value of droppedVerbosity when no message was dropped since the last displayed one
+const noDroppedMessage = VerbosityDebug + 1
+
+// NewTextPrinter creates a printer writing lines to out, and warnings and errors to errOut
+// Colors and line replacement are only used when both writers are terminals,
+// colors are also disabled by NO_COLOR or TERM=dumb
+func NewTextPrinter(out io.Writer, errOut io.Writer) *TextPrinter {
+	interactive := isTerminal(out) && isTerminal(errOut) && os.Getenv("TERM") != "dumb"
+	_, noColor := os.LookupEnv("NO_COLOR")
+	return &TextPrinter{
+		Out:         out,
+		Err:         errOut,
+		Color:       interactive && !noColor,
+		Interactive: interactive,
+	}
+}
+
+// NewJSONPrinter creates a printer writing JSON objects to the given writer
+func NewJSONPrinter(out io.Writer) *JSONPrinter {
+	return &JSONPrinter{Out: out}
+}
+
+// NewPrinter creates the printer for an output format
+// Text warnings and errors go to errOut, JSON objects all go to out
+// Returns an error if the format isn't supported
+func NewPrinter(output string, out io.Writer, errOut io.Writer) (Printer, error) {
+	switch output {
+	case OutputText:
+		return NewTextPrinter(out, errOut), nil
+	case OutputJSON:
+		return NewJSONPrinter(out), nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", output)
+	}
+}
+
+// SetPrinter replaces the printer used by the printing functions
+func SetPrinter(p Printer) {
+	printerMutex.Lock()
+	defer printerMutex.Unlock()
+	printer = p
+	lineDisplayed, droppedVerbosity = false, noDroppedMessage
+}
+
+// GetPrinter returns the printer used by the printing functions
+func GetPrinter() Printer {
+	printerMutex.Lock()
+	defer printerMutex.Unlock()
+	return printer
+}
+
+// SetVerbosity selects which message levels are displayed
+func SetVerbosity(v Verbosity) {
+	printerMutex.Lock()
+	defer printerMutex.Unlock()
+	verbosity = v
+}
+
+// SetComponent sets the component attached to the events that follow
+func SetComponent(name string) {
+	printerMutex.Lock()
+	defer printerMutex.Unlock()
+	component = name
+}
+
+// AddResult attaches a value to the result of the running command
+// Values are only displayed by printers reporting structured results
+func AddResult(key string, value any) {
+	printerMutex.Lock()
+	defer printerMutex.Unlock()
+	resultData[key] = value
+}
+
+// PrintResult displays the outcome of the running command
+// The command failed if it returned an error or printed one
+func PrintResult(err error) {
+	printerMutex.Lock()
+	defer printerMutex.Unlock()
+
+	result := Result{Component: component}
+	if err != nil {
+		result.Error = err.Error()
+	} else if lastError != "" {
+		result.Error = lastError
+	}
+	result.Success = result.Error == ""
+	if len(resultData) > 0 {
+		result.Data = resultData
+	}
+	printer.Result(result)
+}
+
+// Event displays a message as a line, replacing the previous one if requested
+// The previous line can only be replaced if it was written to the same writer
+func (p *TextPrinter) Event(event Event) {
+	out := p.Out
+	if (event.Level == LevelWarning || event.Level == LevelError) && p.Err != nil {
+		out = p.Err
+	}
+	if event.Replace && p.Interactive && out == p.lastOut {
+		fmt.Fprint(out, "\033[1A\033[K") // Move up one line and clear it
+	}
+	p.lastOut = out
+
+	color := levelColor(event.Level)
+	if color == "" || !p.Color {
+		fmt.Fprintf(out, "%s\n", event.Message)
+	} else {
+		fmt.Fprintf(out, "%s%s%s\n", color, event.Message, colorReset)
+	}
+}
+
+// Result is a no-op since text output already displayed every message
+func (p *TextPrinter) Result(result Result) {}
+
+// Event displays a message as a JSON object
+func (p *JSONPrinter) Event(event Event) {
+	p.encode(struct {
+		Type string `json:"type"`
+		Event
+	}{Type: "event", Event: event})
+}
+
+// Result displays the outcome of the command as a JSON object
+func (p *JSONPrinter) Result(result Result) {
+	p.encode(struct {
+		Type string `json:"type"`
+		Result
+	}{Type: "result", Result: result})
+}
+
+// encode writes a value as a single line of JSON
+func (p *JSONPrinter) encode(value any) {
+	encoder := json.NewEncoder(p.Out)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+}
+
+// emit sends a message to the current printer
+func emit(level Level, message string, replace bool) {
+	printerMutex.Lock()
+	defer printerMutex.Unlock()
+
+	if level == LevelError {
+		lastError = message
+	}
+	needed := displayVerbosity(level)
+	if verbosity < needed {
+		droppedVerbosity = min(droppedVerbosity, needed)
+		return
+	}
+
+	// The message being replaced is the last one at least as important, more detailed ones are skipped
+	// It can only be replaced if it was displayed and no more detailed message was displayed after it
+	if replace && (!lineDisplayed || lineVerbosity > needed || droppedVerbosity <= needed) {
+		replace = false
+	}
+	lineDisplayed, lineVerbosity, droppedVerbosity = true, needed, noDroppedMessage
+	printer.Event(Event{Level: level, Message: message, Component: component, Replace: replace})
+}
+
+// displayVerbosity returns the lowest verbosity at which messages of a level are displayed
+func displayVerbosity(level Level) Verbosity {
+	switch level {
+	case LevelError, LevelWarning, LevelOutput:
+		return VerbosityQuiet
+	case LevelVerbose:
+		return VerbosityVerbose
+	case LevelDebug:
+		return VerbosityDebug
+	default:
+		return VerbosityNormal
+	}
+}
+
+// isTerminal checks if a writer is a terminal
+func isTerminal(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// levelColor returns the ANSI color code used for a message level
+func levelColor(level Level) string {
+	switch level {
+	case LevelSuccess:
+		return colorGreen
+	case LevelWarning:
+		return colorYellow
+	case LevelError:
+		return colorRed
+	case LevelDebug:
+		return colorBlue
+	default:
+		return colorWhite
+	}
+}
@@ -0,0 +1,91 @@
+package util
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+// recordingPrinter keeps the events it receives
+type recordingPrinter struct {
+	events []Event
+}
+
+func (p *recordingPrinter) Event(event Event) { p.events = append(p.events, event) }
+
+func (p *recordingPrinter) Result(result Result) {}
+
+func TestReplace(t *testing.T) {
+	type message struct {
+		level   Level
+		text    string
+		replace bool
+	}
+	starting := message{LevelInfo, "Starting..", false}
+	started := message{LevelInfo, "Started", true}
+
+	tests := []struct {
+		name      string
+		verbosity Verbosity
+		messages  []message
+		want      []bool // Replace flag of each displayed event
+	}{
+		{"replaces previous message", VerbosityNormal, []message{starting, started}, []bool{false, true}},
+		{"skips dropped details", VerbosityNormal, []message{starting, {LevelDebug, "Requesting", false}, {LevelVerbose, "Created", false}, started}, []bool{false, true}},
+		{"keeps displayed details", VerbosityDebug, []message{starting, {LevelDebug, "Requesting", false}, started}, []bool{false, false, false}},
+		{"doesn't replace a more detailed message", VerbosityVerbose, []message{{LevelVerbose, "Created", false}, started}, []bool{false, false}},
+		{"doesn't replace when nothing was displayed", VerbosityNormal, []message{started}, []bool{false}},
+		{"replaces important message despite dropped details", VerbosityQuiet, []message{{LevelWarning, "Careful", false}, {LevelInfo, "Info", false}, {LevelWarning, "Done", true}}, []bool{false, true}},
+	}
+	defer SetVerbosity(VerbosityNormal)
+	defer SetPrinter(GetPrinter())
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := &recordingPrinter{}
+			SetPrinter(recorder)
+			SetVerbosity(test.verbosity)
+			for _, m := range test.messages {
+				emit(m.level, m.text, m.replace)
+			}
+
+			got := make([]bool, 0, len(recorder.events))
+			for _, event := range recorder.events {
+				got = append(got, event.Replace)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("replace flags = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTextPrinterStreams(t *testing.T) {
+	var out, errOut bytes.Buffer
+	printer := NewTextPrinter(&out, &errOut)
+	for _, level := range []Level{LevelInfo, LevelOutput, LevelWarning, LevelSuccess, LevelError} {
+		printer.Event(Event{Level: level, Message: string(level)})
+	}
+
+	if got, want := out.String(), "info\noutput\nsuccess\n"; got != want {
+		t.Errorf("out = %q, want %q", got, want)
+	}
+	if got, want := errOut.String(), "warning\nerror\n"; got != want {
+		t.Errorf("errOut = %q, want %q", got, want)
+	}
+}
+
+func TestQuietDisplaysOutput(t *testing.T) {
+	defer SetVerbosity(VerbosityNormal)
+	defer SetPrinter(GetPrinter())
+	recorder := &recordingPrinter{}
+	SetPrinter(recorder)
+	SetVerbosity(VerbosityQuiet)
+
+	PrintInfo("Starting..")
+	PrintOutput("8080")
+	PrintSuccess("Done")
+
+	if len(recorder.events) != 1 || recorder.events[0].Message != "8080" {
+		t.Errorf("quiet output displayed %+v, want only the requested data", recorder.events)
+	}
+}
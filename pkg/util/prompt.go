@@ -0,0 +1,86 @@
+// Package util provides helpers to ask the user for confirmation or to choose between options
+package util
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Reader of the standard input shared by the prompts, so that no answer typed ahead is lost
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// IsInteractive checks if the user can answer prompts on the standard input
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// Confirm asks the user a yes/no question and returns the answer
+// An empty answer, end of input or a non-interactive standard input count as no
+func Confirm(question string) (bool, error) {
+	if !IsInteractive() {
+		return false, nil
+	}
+	PrintWarning(question + " (y/N)")
+
+	answer, err := readAnswer()
+	if err != nil {
+		return false, err
+	}
+	return answer == "y" || answer == "yes", nil
+}
+
+// Choose asks the user to pick one of several options, by name or first letter
+// An empty answer, end of input or a non-interactive standard input pick the default option
+// Parameters:
+//   - question: the question to ask
+//   - options: the names of the options, starting with different letters
+//   - defaultOption: the option picked when the user doesn't answer
+func Choose(question string, options []string, defaultOption string) (string, error) {
+	if !IsInteractive() {
+		return defaultOption, nil
+	}
+
+	choices := make([]string, 0, len(options))
+	for _, option := range options {
+		if option == defaultOption {
+			choices = append(choices, "["+option[:1]+"]"+option[1:])
+		} else {
+			choices = append(choices, "("+option[:1]+")"+option[1:])
+		}
+	}
+
+	for {
+		PrintWarning(question + " " + strings.Join(choices, ", "))
+		answer, err := readAnswer()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			return defaultOption, nil
+		}
+		for _, option := range options {
+			if answer == option || answer == option[:1] {
+				return option, nil
+			}
+		}
+	}
+}
+
+// readAnswer reads a line typed by the user, lowercased and trimmed
+// Returns an empty answer at the end of input
+func readAnswer() (string, error) {
+	answer, err := stdinReader.ReadString('\n')
+	if err != nil && answer == "" {
+		if errors.Is(err, io.EOF) {
+			return "", nil
+		}
+		return "", HandleError("Failed to read user input", err)
+	}
+	PrintEmpty()
+	return strings.ToLower(strings.TrimSpace(answer)), nil
+}